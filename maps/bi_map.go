@@ -0,0 +1,120 @@
+package maps
+
+import "sync"
+
+// BiMapCollisionPolicy defines what BiMap.Store does when the value is
+// already mapped to a different key.
+type BiMapCollisionPolicy int
+
+const (
+	// BiMapReject makes Store fail with ErrValueExists.
+	BiMapReject BiMapCollisionPolicy = iota
+	// BiMapOverwrite removes the other key and maps the value to the new one.
+	BiMapOverwrite
+)
+
+// BiMap is a concurrent one-to-one map that keeps a forward (key to value)
+// and an inverse (value to key) mapping consistent under a single lock.
+// Safe for concurrent use.
+type BiMap[K comparable, V comparable] struct {
+	mu      sync.RWMutex
+	policy  BiMapCollisionPolicy
+	forward map[K]V
+	inverse map[V]K
+}
+
+// NewBiMap returns a new empty BiMap using policy for value collisions.
+func NewBiMap[K comparable, V comparable](policy BiMapCollisionPolicy) *BiMap[K, V] {
+	return &BiMap[K, V]{
+		policy:  policy,
+		forward: make(map[K]V),
+		inverse: make(map[V]K),
+	}
+}
+
+// Store maps key to value and value to key. If key was mapped to another
+// value, that value is released. If value is mapped to another key, the
+// result depends on the collision policy: with BiMapReject, Store returns
+// ErrValueExists and leaves the map unchanged.
+func (b *BiMap[K, V]) Store(key K, value V) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if owner, ok := b.inverse[value]; ok && owner != key {
+		if b.policy == BiMapReject {
+			return ErrValueExists
+		}
+		delete(b.forward, owner)
+	}
+	if old, ok := b.forward[key]; ok {
+		delete(b.inverse, old)
+	}
+	b.forward[key] = value
+	b.inverse[value] = key
+	return nil
+}
+
+// Load returns the value mapped to key.
+func (b *BiMap[K, V]) Load(key K) (V, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	v, ok := b.forward[key]
+	return v, ok
+}
+
+// LoadKey returns the key mapped to value.
+func (b *BiMap[K, V]) LoadKey(value V) (K, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	k, ok := b.inverse[value]
+	return k, ok
+}
+
+// Delete removes key and its value.
+func (b *BiMap[K, V]) Delete(key K) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if v, ok := b.forward[key]; ok {
+		delete(b.forward, key)
+		delete(b.inverse, v)
+	}
+}
+
+// DeleteValue removes value and its key.
+func (b *BiMap[K, V]) DeleteValue(value V) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if k, ok := b.inverse[value]; ok {
+		delete(b.inverse, value)
+		delete(b.forward, k)
+	}
+}
+
+func (b *BiMap[K, V]) Len() int64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return int64(len(b.forward))
+}
+
+// Range calls f for each pair until f returns false. It iterates over a
+// copy taken under the lock, so f may modify the map.
+func (b *BiMap[K, V]) Range(f func(key K, value V) bool) {
+	if f == nil {
+		return
+	}
+
+	b.mu.RLock()
+	keys := make([]K, 0, len(b.forward))
+	values := make([]V, 0, len(b.forward))
+	for k, v := range b.forward {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	b.mu.RUnlock()
+
+	for i := range keys {
+		if !f(keys[i], values[i]) {
+			return
+		}
+	}
+}
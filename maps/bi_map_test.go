@@ -0,0 +1,147 @@
+package maps
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestBiMap_StoreLoad(t *testing.T) {
+	m := NewBiMap[int, string](BiMapReject)
+	if err := m.Store(1, "one"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, ok := m.Load(1); !ok || v != "one" {
+		t.Fatalf("expected (\"one\", true), got (%v, %v)", v, ok)
+	}
+	if k, ok := m.LoadKey("one"); !ok || k != 1 {
+		t.Fatalf("expected (1, true), got (%v, %v)", k, ok)
+	}
+	if err := m.Store(1, "one"); err != nil {
+		t.Fatalf("expected storing the same pair to succeed, got %v", err)
+	}
+
+	// re-mapping a key releases its old value
+	if err := m.Store(1, "uno"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := m.LoadKey("one"); ok {
+		t.Fatal("expected old value to be released")
+	}
+	if m.Len() != 1 {
+		t.Fatalf("expected len 1, got %d", m.Len())
+	}
+}
+
+func TestBiMap_Reject(t *testing.T) {
+	m := NewBiMap[int, string](BiMapReject)
+	_ = m.Store(1, "one")
+	if err := m.Store(2, "one"); !errors.Is(err, ErrValueExists) {
+		t.Fatalf("expected ErrValueExists, got %v", err)
+	}
+	if _, ok := m.Load(2); ok {
+		t.Fatal("expected rejected key not to be stored")
+	}
+	if k, _ := m.LoadKey("one"); k != 1 {
+		t.Fatalf("expected value to stay mapped to 1, got %d", k)
+	}
+}
+
+func TestBiMap_Overwrite(t *testing.T) {
+	m := NewBiMap[int, string](BiMapOverwrite)
+	_ = m.Store(1, "one")
+	_ = m.Store(2, "two")
+	if err := m.Store(2, "one"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := m.Load(1); ok {
+		t.Fatal("expected previous owner of the value to be removed")
+	}
+	if _, ok := m.LoadKey("two"); ok {
+		t.Fatal("expected previous value of the key to be released")
+	}
+	if k, _ := m.LoadKey("one"); k != 2 {
+		t.Fatalf("expected value to be mapped to 2, got %d", k)
+	}
+	if m.Len() != 1 {
+		t.Fatalf("expected len 1, got %d", m.Len())
+	}
+}
+
+func TestBiMap_Delete(t *testing.T) {
+	m := NewBiMap[int, string](BiMapReject)
+	_ = m.Store(1, "one")
+	_ = m.Store(2, "two")
+	m.Delete(1)
+	m.DeleteValue("two")
+	m.Delete(42)
+	m.DeleteValue("missing")
+	if m.Len() != 0 {
+		t.Fatalf("expected len 0, got %d", m.Len())
+	}
+	if _, ok := m.LoadKey("one"); ok {
+		t.Fatal("expected inverse entry to be removed by Delete")
+	}
+	if _, ok := m.Load(2); ok {
+		t.Fatal("expected forward entry to be removed by DeleteValue")
+	}
+}
+
+func TestBiMap_Range(t *testing.T) {
+	m := NewBiMap[int, string](BiMapReject)
+	_ = m.Store(1, "one")
+	_ = m.Store(2, "two")
+
+	collected := make(map[int]string)
+	m.Range(func(k int, v string) bool {
+		collected[k] = v
+		m.Delete(k) // modifying the map from f must not deadlock
+		return true
+	})
+	if len(collected) != 2 || collected[2] != "two" {
+		t.Fatalf("unexpected collected pairs %v", collected)
+	}
+
+	_ = m.Store(1, "one")
+	_ = m.Store(2, "two")
+	times := 0
+	m.Range(func(k int, v string) bool {
+		times++
+		return false
+	})
+	if times != 1 {
+		t.Fatalf("expected Range to stop after first iteration, got %d iterations", times)
+	}
+	m.Range(nil)
+}
+
+// Concurrent writers must never leave the forward and inverse mappings out of sync.
+func TestBiMap_ConcurrentConsistency(t *testing.T) {
+	m := NewBiMap[int, string](BiMapOverwrite)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				_ = m.Store((w+j)%10, fmt.Sprint(j%7))
+				if j%5 == 0 {
+					m.DeleteValue(fmt.Sprint(j % 7))
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(m.forward) != len(m.inverse) {
+		t.Fatalf("forward has %d entries, inverse %d", len(m.forward), len(m.inverse))
+	}
+	for k, v := range m.forward {
+		if m.inverse[v] != k {
+			t.Fatalf("inverse of %q is %d, want %d", v, m.inverse[v], k)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package maps
+
+import "time"
+
+// ConcurrentMap is the method set shared by the concurrent maps of this
+// package, so implementations can be swapped behind an interface.
+// Implementations are safe for concurrent use.
+//
+// Not every map implements it: BiMap and DurableMap writes return an error,
+// MultiMap ranges over slices of values, and OrderedMap and SortedMap are
+// not safe for concurrent use (see SyncOrderedMap and SyncSortedMap).
+//
+// There is no capability interface for size-bounded maps, as no map in
+// this package has a capacity limit.
+type ConcurrentMap[K comparable, V any] interface {
+	Ranger[K, V]
+	Store(key K, value V)
+	Load(key K) (V, bool)
+	Delete(key K)
+	Len() int64
+}
+
+// Updater is implemented by maps that can atomically read-modify-write an entry.
+type Updater[K comparable, V any] interface {
+	Update(key K, fn func(old V, ok bool) V) V
+	Compute(key K, fn func(old V, ok bool) (value V, keep bool)) (V, bool)
+}
+
+// Snapshotter is implemented by maps that can take a consistent point-in-time copy.
+type Snapshotter[K comparable, V any] interface {
+	Snapshot() Snapshot[K, V]
+}
+
+// Expiring is implemented by maps whose entries expire after a period of
+// inactivity; TTL returns that period. IndexedMap implements it whether or
+// not it was created with expiry and reports zero if it was not.
+type Expiring interface {
+	TTL() time.Duration
+}
+
+var (
+	_ ConcurrentMap[string, int]  = (*TypedSyncMap[string, int])(nil)
+	_ ConcurrentMap[string, int]  = (*TtlTypedSyncMap[string, int])(nil)
+	_ ConcurrentMap[string, int]  = (*ShardedMap[string, int])(nil)
+	_ ConcurrentMap[string, int]  = (*COWMap[string, int])(nil)
+	_ ConcurrentMap[string, int]  = (*SyncOrderedMap[string, int])(nil)
+	_ ConcurrentMap[string, int]  = (*SyncSortedMap[string, int])(nil)
+	_ ConcurrentMap[string, int]  = (*VersionedMap[string, int])(nil)
+	_ ConcurrentMap[string, int]  = (*LWWMap[string, int])(nil)
+	_ ConcurrentMap[string, int]  = (*IndexedMap[string, int, string])(nil)
+	_ ConcurrentMap[string, int]  = (*Namespace[string, int])(nil)
+	_ ConcurrentMap[string, int]  = (*RadixMap[string, int])(nil)
+	_ ConcurrentMap[string, *int] = (*WeakValueMap[string, int])(nil)
+	_ ConcurrentMap[*string, int] = (*WeakKeyMap[string, int])(nil)
+
+	_ Updater[string, int] = (*TypedSyncMap[string, int])(nil)
+	_ Updater[string, int] = (*ShardedMap[string, int])(nil)
+
+	_ Snapshotter[string, int] = (*TypedSyncMap[string, int])(nil)
+	_ Snapshotter[string, int] = (*COWMap[string, int])(nil)
+
+	_ Expiring = (*TtlTypedSyncMap[string, int])(nil)
+	_ Expiring = (*Namespace[string, int])(nil)
+	_ Expiring = (*IndexedMap[string, int, string])(nil)
+)
@@ -0,0 +1,60 @@
+package maps_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NLipatov/goutils/maps"
+	"github.com/NLipatov/goutils/maps/mapstest"
+)
+
+// conformanceCases lists a constructor for every ConcurrentMap implementation
+// the shared suite runs against.
+func conformanceCases(ctx context.Context) map[string]func() maps.ConcurrentMap[string, int] {
+	return map[string]func() maps.ConcurrentMap[string, int]{
+		"TypedSyncMap": func() maps.ConcurrentMap[string, int] { return maps.NewTypedSyncMap[string, int]() },
+		"TtlTypedSyncMap": func() maps.ConcurrentMap[string, int] {
+			return maps.NewTtlTypedSyncMap[string, int](ctx, time.Hour, time.Hour)
+		},
+		"ShardedMap":     func() maps.ConcurrentMap[string, int] { return maps.NewShardedMap[string, int](4, nil) },
+		"COWMap":         func() maps.ConcurrentMap[string, int] { return maps.NewCOWMap[string, int]() },
+		"SyncOrderedMap": func() maps.ConcurrentMap[string, int] { return maps.NewSyncOrderedMap[string, int]() },
+		"SyncSortedMap":  func() maps.ConcurrentMap[string, int] { return maps.NewSyncSortedMap[string, int]() },
+		"VersionedMap":   func() maps.ConcurrentMap[string, int] { return maps.NewVersionedMap[string, int]() },
+		"LWWMap":         func() maps.ConcurrentMap[string, int] { return maps.NewLWWMap[string, int]("node", time.Now) },
+		"IndexedMap":     func() maps.ConcurrentMap[string, int] { return maps.NewIndexedMap[string, int, string]() },
+		"Namespace": func() maps.ConcurrentMap[string, int] {
+			shared := maps.NewTtlTypedSyncMap[maps.NamespacedKey[string], int](ctx, time.Hour, time.Hour)
+			// entries of other namespaces must stay invisible
+			maps.NewNamespace[string, int](shared, "other").Store("a", 42)
+			return maps.NewNamespace[string, int](shared, "ns")
+		},
+		"RadixMap": func() maps.ConcurrentMap[string, int] { return maps.NewRadixMap[string, int]() },
+	}
+}
+
+func TestConcurrentMap_Conformance(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for name, newMap := range conformanceCases(ctx) {
+		t.Run(name, func(t *testing.T) {
+			mapstest.RunConformance(t, newMap)
+		})
+	}
+}
+
+func TestTtlTypedSyncMap_TTL(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var m maps.ConcurrentMap[string, int] = maps.NewTtlTypedSyncMap[string, int](ctx, time.Minute, 0)
+	exp, ok := m.(maps.Expiring)
+	if !ok || exp.TTL() != time.Minute {
+		t.Fatalf("expected an Expiring map with one-minute TTL, got %v", exp)
+	}
+	if _, ok := maps.ConcurrentMap[string, int](maps.NewTypedSyncMap[string, int]()).(maps.Expiring); ok {
+		t.Fatal("expected TypedSyncMap not to be Expiring")
+	}
+}
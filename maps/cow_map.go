@@ -0,0 +1,78 @@
+package maps
+
+import (
+	stdmaps "maps"
+	"sync"
+	"sync/atomic"
+)
+
+// COWMap is a copy-on-write map optimised for data that is read far more
+// often than it is written.
+//
+// Readers load an immutable map through an atomic pointer and never lock.
+// Every write clones the whole map, so writes are O(n); use Batch to apply
+// several changes with a single clone.
+type COWMap[K comparable, V any] struct {
+	mu    sync.Mutex // serialises writers
+	items atomic.Pointer[map[K]V]
+}
+
+func NewCOWMap[K comparable, V any]() *COWMap[K, V] {
+	res := &COWMap[K, V]{}
+	items := make(map[K]V)
+	res.items.Store(&items)
+	return res
+}
+
+func (c *COWMap[K, V]) Store(key K, value V) {
+	c.Batch(func(items map[K]V) {
+		items[key] = value
+	})
+}
+
+func (c *COWMap[K, V]) Load(key K) (V, bool) {
+	v, ok := (*c.items.Load())[key]
+	return v, ok
+}
+
+func (c *COWMap[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	current := *c.items.Load()
+	if _, ok := current[key]; !ok {
+		return
+	}
+	next := stdmaps.Clone(current)
+	delete(next, key)
+	c.items.Store(&next)
+}
+
+func (c *COWMap[K, V]) Len() int64 {
+	return int64(len(*c.items.Load()))
+}
+
+// Range iterates over the contents of the map at the moment Range was
+// called. Writes made by f are not observed by the ongoing iteration.
+func (c *COWMap[K, V]) Range(f func(key K, value V) bool) {
+	c.Snapshot().Range(f)
+}
+
+// Batch clones the map once, passes the clone to fn for modification and
+// publishes the result atomically, so readers observe either none or all of
+// fn's changes. fn must not retain items or call other methods of c that
+// write to it.
+func (c *COWMap[K, V]) Batch(fn func(items map[K]V)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	next := stdmaps.Clone(*c.items.Load())
+	fn(next)
+	c.items.Store(&next)
+}
+
+// Snapshot returns a stable read-only view of the current contents.
+// It does not copy: the view shares the immutable map readers use.
+func (c *COWMap[K, V]) Snapshot() Snapshot[K, V] {
+	return Snapshot[K, V]{items: *c.items.Load()}
+}
@@ -0,0 +1,120 @@
+package maps
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCOWMap_StoreLoadDelete(t *testing.T) {
+	m := NewCOWMap[string, int]()
+	m.Store("a", 1)
+	m.Store("b", 2)
+	m.Store("a", 3)
+
+	if v, ok := m.Load("a"); !ok || v != 3 {
+		t.Fatalf("expected (3, true), got (%v, %v)", v, ok)
+	}
+	if m.Len() != 2 {
+		t.Fatalf("expected len 2, got %d", m.Len())
+	}
+
+	m.Delete("a")
+	m.Delete("missing")
+	if _, ok := m.Load("a"); ok {
+		t.Fatal("expected key to be deleted")
+	}
+	if m.Len() != 1 {
+		t.Fatalf("expected len 1 after delete, got %d", m.Len())
+	}
+}
+
+func TestCOWMap_Batch(t *testing.T) {
+	m := NewCOWMap[int, string]()
+	m.Store(1, "one")
+
+	before := m.Snapshot()
+	m.Batch(func(items map[int]string) {
+		delete(items, 1)
+		items[2] = "two"
+		items[3] = "three"
+	})
+
+	if m.Len() != 2 {
+		t.Fatalf("expected len 2 after batch, got %d", m.Len())
+	}
+	if _, ok := m.Load(1); ok {
+		t.Fatal("expected key 1 to be deleted by batch")
+	}
+	if v, ok := before.Load(1); !ok || v != "one" {
+		t.Fatalf("expected snapshot taken before batch to keep (\"one\", true), got (%v, %v)", v, ok)
+	}
+	if before.Len() != 1 {
+		t.Fatalf("expected snapshot len 1, got %d", before.Len())
+	}
+}
+
+func TestCOWMap_RangeSeesStableView(t *testing.T) {
+	m := NewCOWMap[int, int]()
+	for i := 0; i < 10; i++ {
+		m.Store(i, i)
+	}
+
+	seen := 0
+	m.Range(func(k, v int) bool {
+		m.Store(k+100, v) // writes from f must not deadlock or be observed
+		seen++
+		return true
+	})
+	if seen != 10 {
+		t.Fatalf("expected Range to visit 10 entries, got %d", seen)
+	}
+	if m.Len() != 20 {
+		t.Fatalf("expected len 20 after writes in Range, got %d", m.Len())
+	}
+}
+
+func TestCOWMap_ConcurrentReadersAndWriters(t *testing.T) {
+	m := NewCOWMap[int, int]()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(k int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m.Store(k, j)
+			}
+		}(i)
+		go func(k int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m.Load(k)
+				m.Snapshot().Len()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if m.Len() != 10 {
+		t.Fatalf("expected len 10, got %d", m.Len())
+	}
+	for i := 0; i < 10; i++ {
+		if v, _ := m.Load(i); v != 99 {
+			t.Fatalf("expected last write 99 for key %d, got %d", i, v)
+		}
+	}
+}
+
+func BenchmarkCOWMap_Load(b *testing.B) {
+	m := NewCOWMap[int, int]()
+	m.Batch(func(items map[int]int) {
+		for i := 0; i < 1024; i++ {
+			items[i] = i
+		}
+	})
+	b.RunParallel(func(pb *testing.PB) {
+		k := 0
+		for pb.Next() {
+			m.Load(k & 1023)
+			k++
+		}
+	})
+}
@@ -0,0 +1,402 @@
+package maps
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	durableWALFile      = "wal.log"
+	durableSnapshotFile = "snapshot.gob"
+
+	// walHeaderSize is the size of a record header: payload length and CRC-32.
+	walHeaderSize = 8
+	// walMaxRecordSize guards replay against allocating for a corrupt length.
+	walMaxRecordSize = 64 << 20
+)
+
+const (
+	walOpStore byte = iota
+	walOpDelete
+)
+
+// SyncPolicy defines when DurableMap flushes its write-ahead log to stable storage.
+type SyncPolicy int
+
+const (
+	// SyncAlways fsyncs after every write: no acknowledged write is lost on a crash.
+	SyncAlways SyncPolicy = iota
+	// SyncInterval fsyncs in the background every DurableOptions.SyncInterval:
+	// a crash loses at most the writes of the last interval.
+	SyncInterval
+	// SyncNever leaves flushing to the operating system.
+	SyncNever
+)
+
+// DurableOptions configures OpenDurableMap.
+type DurableOptions struct {
+	Sync SyncPolicy
+	// SyncInterval is the fsync period for SyncInterval; defaults to one second.
+	SyncInterval time.Duration
+	// CompactInterval enables periodic compaction when positive.
+	CompactInterval time.Duration
+}
+
+// DurableMap is a TypedSyncMap persisted to a directory: every Store and
+// Delete is appended to a write-ahead log before it is applied, and the log
+// is replayed on open. Compact folds the log into a snapshot file.
+// Safe for concurrent use; a directory must be opened by one DurableMap at a time.
+//
+// Keys and values are encoded with encoding/gob, so they must be gob-encodable.
+// Reads are served from memory and never touch the disk.
+type DurableMap[K comparable, V any] struct {
+	dir  string
+	opts DurableOptions
+	m    *TypedSyncMap[K, V]
+
+	mu  sync.Mutex // serialises log writes, compaction and Close
+	wal walFile
+	// offset is the end of the last complete record in the log.
+	offset int64
+	dirty  bool
+	closed bool
+	// failed is set when a failed write could not be rolled back;
+	// the log can no longer be trusted and writes are refused.
+	failed error
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// walFile is the part of *os.File the log is written through.
+type walFile interface {
+	io.Writer
+	Sync() error
+	Truncate(size int64) error
+	Seek(offset int64, whence int) (int64, error)
+	Close() error
+}
+
+type walRecord[K comparable, V any] struct {
+	Op    byte
+	Key   K
+	Value V
+}
+
+// OpenDurableMap opens or creates a DurableMap in dir. A log tail left
+// incomplete by a crash is discarded.
+func OpenDurableMap[K comparable, V any](dir string, opts DurableOptions) (*DurableMap[K, V], error) {
+	if opts.SyncInterval <= 0 {
+		opts.SyncInterval = time.Second
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	d := &DurableMap[K, V]{
+		dir:  dir,
+		opts: opts,
+	}
+	if err := d.loadSnapshot(); err != nil {
+		return nil, err
+	}
+
+	wal, err := os.OpenFile(filepath.Join(dir, durableWALFile), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.replay(wal); err != nil {
+		wal.Close()
+		return nil, err
+	}
+
+	if opts.Sync == SyncInterval || opts.CompactInterval > 0 {
+		d.stop = make(chan struct{})
+		d.done = make(chan struct{})
+		go d.background()
+	}
+	return d, nil
+}
+
+func (d *DurableMap[K, V]) Store(key K, value V) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.append(walRecord[K, V]{Op: walOpStore, Key: key, Value: value}); err != nil {
+		return err
+	}
+	d.m.Store(key, value)
+	return nil
+}
+
+func (d *DurableMap[K, V]) Load(key K) (V, bool) {
+	return d.m.Load(key)
+}
+
+func (d *DurableMap[K, V]) Delete(key K) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.append(walRecord[K, V]{Op: walOpDelete, Key: key}); err != nil {
+		return err
+	}
+	d.m.Delete(key)
+	return nil
+}
+
+func (d *DurableMap[K, V]) Len() int64 {
+	return d.m.Len()
+}
+
+func (d *DurableMap[K, V]) Range(f func(key K, value V) bool) {
+	d.m.Range(f)
+}
+
+// Compact writes the current contents to the snapshot file and truncates
+// the log. The snapshot is written to a temporary file and renamed, so a
+// crash during compaction leaves the previous state intact. Compact also
+// recovers a map whose writes fail with ErrDurableMapFailed.
+func (d *DurableMap[K, V]) Compact() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrDurableMapClosed
+	}
+	return d.compact()
+}
+
+// Close flushes the log and releases the directory. Further writes fail
+// with ErrDurableMapClosed; reads keep serving the in-memory contents.
+func (d *DurableMap[K, V]) Close() error {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil
+	}
+	d.closed = true
+	d.mu.Unlock()
+
+	if d.stop != nil {
+		close(d.stop)
+		<-d.done
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return errors.Join(d.wal.Sync(), d.wal.Close())
+}
+
+// append writes rec to the log according to the sync policy. If the write
+// or the fsync fails, the log is rolled back to the previous record so a
+// partial record cannot hide the records appended after it on replay.
+// Callers must hold d.mu.
+func (d *DurableMap[K, V]) append(rec walRecord[K, V]) error {
+	if d.closed {
+		return ErrDurableMapClosed
+	}
+	if d.failed != nil {
+		return errors.Join(ErrDurableMapFailed, d.failed)
+	}
+
+	payload, err := gobEncode(rec)
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, walHeaderSize+len(payload))
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(buf[4:8], crc32.ChecksumIEEE(payload))
+	copy(buf[walHeaderSize:], payload)
+
+	if _, err := d.wal.Write(buf); err != nil {
+		return d.rollback(err)
+	}
+	if d.opts.Sync == SyncAlways {
+		if err := d.wal.Sync(); err != nil {
+			return d.rollback(err)
+		}
+	}
+	d.offset += int64(len(buf))
+	d.dirty = true
+	return nil
+}
+
+// rollback truncates the log back to d.offset after err and returns err.
+// If that fails too, the map refuses further writes. Callers must hold d.mu.
+func (d *DurableMap[K, V]) rollback(err error) error {
+	if rbErr := d.truncate(d.offset); rbErr != nil {
+		d.failed = errors.Join(err, rbErr)
+		return errors.Join(ErrDurableMapFailed, d.failed)
+	}
+	return err
+}
+
+// truncate cuts the log at offset and positions it for appending there.
+// Callers must hold d.mu.
+func (d *DurableMap[K, V]) truncate(offset int64) error {
+	if err := d.wal.Truncate(offset); err != nil {
+		return err
+	}
+	_, err := d.wal.Seek(offset, io.SeekStart)
+	return err
+}
+
+func (d *DurableMap[K, V]) loadSnapshot() error {
+	data, err := os.ReadFile(filepath.Join(d.dir, durableSnapshotFile))
+	if errors.Is(err, os.ErrNotExist) {
+		d.m = NewTypedSyncMap[K, V]()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var items map[K]V
+	if err := gobDecode(data, &items); err != nil {
+		return err
+	}
+	d.m = NewFromMap(items)
+	return nil
+}
+
+// replay applies the records of wal, makes it the map's log and truncates
+// it after the last complete record, leaving it positioned for appending.
+func (d *DurableMap[K, V]) replay(wal *os.File) error {
+	r := bufio.NewReader(wal)
+	var offset int64
+	header := make([]byte, walHeaderSize)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			return err
+		}
+		size := binary.LittleEndian.Uint32(header[0:4])
+		if size > walMaxRecordSize {
+			break
+		}
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			return err
+		}
+		if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(header[4:8]) {
+			break
+		}
+
+		var rec walRecord[K, V]
+		if err := gobDecode(payload, &rec); err != nil {
+			return err
+		}
+		switch rec.Op {
+		case walOpStore:
+			d.m.Store(rec.Key, rec.Value)
+		case walOpDelete:
+			d.m.Delete(rec.Key)
+		}
+		offset += walHeaderSize + int64(size)
+	}
+
+	d.wal = wal
+	d.offset = offset
+	return d.truncate(offset)
+}
+
+// compact must be called with d.mu held.
+func (d *DurableMap[K, V]) compact() error {
+	data, err := gobEncode(d.m.ToMap())
+	if err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(d.dir, durableSnapshotFile+".tmp")
+	if err := writeFileSync(tmp, data); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(d.dir, durableSnapshotFile)); err != nil {
+		return err
+	}
+	if err := syncDir(d.dir); err != nil {
+		return err
+	}
+
+	// the snapshot holds every acknowledged write: a crash before the
+	// truncation only replays the old log over it, which is harmless, and
+	// an empty log also recovers a map that failed to roll back a write
+	if err := d.truncate(0); err != nil {
+		d.failed = err
+		return errors.Join(ErrDurableMapFailed, err)
+	}
+	d.offset = 0
+	d.dirty = false
+	d.failed = nil
+	return d.wal.Sync()
+}
+
+func (d *DurableMap[K, V]) background() {
+	defer close(d.done)
+
+	var syncC, compactC <-chan time.Time
+	if d.opts.Sync == SyncInterval {
+		t := time.NewTicker(d.opts.SyncInterval)
+		defer t.Stop()
+		syncC = t.C
+	}
+	if d.opts.CompactInterval > 0 {
+		t := time.NewTicker(d.opts.CompactInterval)
+		defer t.Stop()
+		compactC = t.C
+	}
+
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-syncC:
+			d.mu.Lock()
+			if d.dirty && !d.closed {
+				if d.wal.Sync() == nil {
+					d.dirty = false
+				}
+			}
+			d.mu.Unlock()
+		case <-compactC:
+			d.mu.Lock()
+			if !d.closed {
+				// a failed compaction leaves the log intact; retried next tick
+				_ = d.compact()
+			}
+			d.mu.Unlock()
+		}
+	}
+}
+
+func writeFileSync(name string, data []byte) error {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return errors.Join(f.Sync(), f.Close())
+}
+
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	return errors.Join(f.Sync(), f.Close())
+}
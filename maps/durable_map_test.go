@@ -0,0 +1,324 @@
+package maps
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func openDurable(t *testing.T, dir string, opts DurableOptions) *DurableMap[string, int] {
+	t.Helper()
+	d, err := OpenDurableMap[string, int](dir, opts)
+	if err != nil {
+		t.Fatalf("unexpected open error: %v", err)
+	}
+	return d
+}
+
+func TestDurableMap_ReplayAfterReopen(t *testing.T) {
+	dir := t.TempDir()
+	d := openDurable(t, dir, DurableOptions{})
+	for k, v := range map[string]int{"a": 1, "b": 2, "c": 3} {
+		if err := d.Store(k, v); err != nil {
+			t.Fatalf("unexpected store error: %v", err)
+		}
+	}
+	d.Store("a", 10)
+	if err := d.Delete("b"); err != nil {
+		t.Fatalf("unexpected delete error: %v", err)
+	}
+	if err := d.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	d = openDurable(t, dir, DurableOptions{})
+	defer d.Close()
+	if d.Len() != 2 {
+		t.Fatalf("expected len 2, got %d", d.Len())
+	}
+	if v, ok := d.Load("a"); !ok || v != 10 {
+		t.Fatalf("expected (10, true), got (%v, %v)", v, ok)
+	}
+	if _, ok := d.Load("b"); ok {
+		t.Fatal("expected deleted key to stay deleted")
+	}
+}
+
+func TestDurableMap_CompactThenReopen(t *testing.T) {
+	dir := t.TempDir()
+	d := openDurable(t, dir, DurableOptions{Sync: SyncNever})
+	d.Store("a", 1)
+	d.Store("b", 2)
+	if err := d.Compact(); err != nil {
+		t.Fatalf("unexpected compact error: %v", err)
+	}
+	if info, err := os.Stat(filepath.Join(dir, durableWALFile)); err != nil || info.Size() != 0 {
+		t.Fatalf("expected empty log after compaction, got %v, %v", info, err)
+	}
+	d.Store("c", 3)
+	d.Delete("a")
+	d.Close()
+
+	d = openDurable(t, dir, DurableOptions{})
+	defer d.Close()
+	got := d.m.ToMap()
+	if len(got) != 2 || got["b"] != 2 || got["c"] != 3 {
+		t.Fatalf("expected map[b:2 c:3], got %v", got)
+	}
+}
+
+func TestDurableMap_TruncatesTornTail(t *testing.T) {
+	dir := t.TempDir()
+	d := openDurable(t, dir, DurableOptions{})
+	d.Store("a", 1)
+	d.Store("b", 2)
+	d.Close()
+
+	// simulate a crash in the middle of appending the last record
+	walPath := filepath.Join(dir, durableWALFile)
+	info, err := os.Stat(walPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(walPath, info.Size()-3); err != nil {
+		t.Fatal(err)
+	}
+
+	d = openDurable(t, dir, DurableOptions{})
+	if _, ok := d.Load("b"); ok {
+		t.Fatal("expected torn record to be discarded")
+	}
+	if v, ok := d.Load("a"); !ok || v != 1 {
+		t.Fatalf("expected (1, true), got (%v, %v)", v, ok)
+	}
+
+	// new records must follow the last complete one
+	d.Store("c", 3)
+	d.Close()
+	d = openDurable(t, dir, DurableOptions{})
+	defer d.Close()
+	if d.Len() != 2 {
+		t.Fatalf("expected len 2, got %d", d.Len())
+	}
+}
+
+func TestDurableMap_DiscardsCorruptTail(t *testing.T) {
+	dir := t.TempDir()
+	d := openDurable(t, dir, DurableOptions{})
+	d.Store("a", 1)
+	d.Store("b", 2)
+	d.Close()
+
+	walPath := filepath.Join(dir, durableWALFile)
+	data, err := os.ReadFile(walPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)-1] ^= 0xff
+	if err := os.WriteFile(walPath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	d = openDurable(t, dir, DurableOptions{})
+	defer d.Close()
+	if _, ok := d.Load("b"); ok {
+		t.Fatal("expected record with bad checksum to be discarded")
+	}
+	if d.Len() != 1 {
+		t.Fatalf("expected len 1, got %d", d.Len())
+	}
+}
+
+func TestDurableMap_Closed(t *testing.T) {
+	d := openDurable(t, t.TempDir(), DurableOptions{Sync: SyncInterval, SyncInterval: time.Millisecond})
+	d.Store("a", 1)
+	if err := d.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	if err := d.Close(); err != nil {
+		t.Fatalf("expected repeated Close to succeed, got %v", err)
+	}
+	if err := d.Store("b", 2); !errors.Is(err, ErrDurableMapClosed) {
+		t.Fatalf("expected ErrDurableMapClosed, got %v", err)
+	}
+	if err := d.Delete("a"); !errors.Is(err, ErrDurableMapClosed) {
+		t.Fatalf("expected ErrDurableMapClosed, got %v", err)
+	}
+	if err := d.Compact(); !errors.Is(err, ErrDurableMapClosed) {
+		t.Fatalf("expected ErrDurableMapClosed, got %v", err)
+	}
+	if v, ok := d.Load("a"); !ok || v != 1 {
+		t.Fatalf("expected reads to keep working, got (%v, %v)", v, ok)
+	}
+}
+
+func TestDurableMap_PeriodicCompaction(t *testing.T) {
+	dir := t.TempDir()
+	d := openDurable(t, dir, DurableOptions{Sync: SyncInterval, SyncInterval: time.Millisecond, CompactInterval: 5 * time.Millisecond})
+	d.Store("a", 1)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := os.Stat(filepath.Join(dir, durableSnapshotFile)); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected background compaction to write a snapshot")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	d.Close()
+
+	d = openDurable(t, dir, DurableOptions{})
+	defer d.Close()
+	if v, ok := d.Load("a"); !ok || v != 1 {
+		t.Fatalf("expected (1, true), got (%v, %v)", v, ok)
+	}
+}
+
+func TestDurableMap_Concurrent(t *testing.T) {
+	dir := t.TempDir()
+	d := openDurable(t, dir, DurableOptions{Sync: SyncNever})
+	wg := sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			d.Store(string(rune('a'+i%26)), i)
+			if i%10 == 0 {
+				d.Compact()
+			}
+		}(i)
+	}
+	wg.Wait()
+	want := d.m.ToMap()
+	d.Close()
+
+	d = openDurable(t, dir, DurableOptions{})
+	defer d.Close()
+	if got := d.m.ToMap(); len(got) != len(want) {
+		t.Fatalf("expected %d entries after reopen, got %d", len(want), len(got))
+	}
+	for k, v := range want {
+		if got, ok := d.Load(k); !ok || got != v {
+			t.Fatalf("expected (%d, true) for %q, got (%d, %v)", v, k, got, ok)
+		}
+	}
+}
+
+// faultyWAL wraps the log file and injects faults: the next write stops
+// halfway, the next fsync fails, or every truncation fails.
+type faultyWAL struct {
+	walFile
+	failWrite bool
+	failSync  bool
+	failTrunc bool
+}
+
+var errInjected = errors.New("injected fault")
+
+func (f *faultyWAL) Write(p []byte) (int, error) {
+	if f.failWrite {
+		f.failWrite = false
+		n, _ := f.walFile.Write(p[:len(p)/2])
+		return n, errInjected
+	}
+	return f.walFile.Write(p)
+}
+
+func (f *faultyWAL) Sync() error {
+	if f.failSync {
+		f.failSync = false
+		return errInjected
+	}
+	return f.walFile.Sync()
+}
+
+func (f *faultyWAL) Truncate(size int64) error {
+	if f.failTrunc {
+		return errInjected
+	}
+	return f.walFile.Truncate(size)
+}
+
+func TestDurableMap_ShortWriteIsRolledBack(t *testing.T) {
+	dir := t.TempDir()
+	d := openDurable(t, dir, DurableOptions{})
+	faulty := &faultyWAL{walFile: d.wal}
+	d.wal = faulty
+
+	d.Store("a", 1)
+	faulty.failWrite = true
+	if err := d.Store("b", 2); !errors.Is(err, errInjected) {
+		t.Fatalf("expected injected error, got %v", err)
+	}
+	if _, ok := d.Load("b"); ok {
+		t.Fatal("expected failed write not to be applied")
+	}
+	// writes acknowledged after the failure must survive a restart
+	if err := d.Store("c", 3); err != nil {
+		t.Fatalf("unexpected store error: %v", err)
+	}
+	d.Close()
+
+	d = openDurable(t, dir, DurableOptions{})
+	defer d.Close()
+	if got := d.m.ToMap(); len(got) != 2 || got["a"] != 1 || got["c"] != 3 {
+		t.Fatalf("expected map[a:1 c:3], got %v", got)
+	}
+}
+
+func TestDurableMap_FailedSyncIsRolledBack(t *testing.T) {
+	dir := t.TempDir()
+	d := openDurable(t, dir, DurableOptions{Sync: SyncAlways})
+	faulty := &faultyWAL{walFile: d.wal, failSync: true}
+	d.wal = faulty
+
+	if err := d.Store("a", 1); !errors.Is(err, errInjected) {
+		t.Fatalf("expected injected error, got %v", err)
+	}
+	d.Store("b", 2)
+	d.Close()
+
+	d = openDurable(t, dir, DurableOptions{})
+	defer d.Close()
+	if _, ok := d.Load("a"); ok {
+		t.Fatal("expected write reported as failed not to be replayed")
+	}
+	if v, ok := d.Load("b"); !ok || v != 2 {
+		t.Fatalf("expected (2, true), got (%v, %v)", v, ok)
+	}
+}
+
+func TestDurableMap_FailedRollbackRefusesWrites(t *testing.T) {
+	dir := t.TempDir()
+	d := openDurable(t, dir, DurableOptions{})
+	faulty := &faultyWAL{walFile: d.wal, failWrite: true, failTrunc: true}
+	d.wal = faulty
+
+	if err := d.Store("a", 1); !errors.Is(err, ErrDurableMapFailed) {
+		t.Fatalf("expected ErrDurableMapFailed, got %v", err)
+	}
+	if err := d.Store("b", 2); !errors.Is(err, ErrDurableMapFailed) {
+		t.Fatalf("expected writes to be refused, got %v", err)
+	}
+
+	// compaction replaces the untrusted log
+	faulty.failTrunc = false
+	if err := d.Compact(); err != nil {
+		t.Fatalf("unexpected compact error: %v", err)
+	}
+	if err := d.Store("c", 3); err != nil {
+		t.Fatalf("expected compaction to recover the map, got %v", err)
+	}
+	d.Close()
+
+	d = openDurable(t, dir, DurableOptions{})
+	defer d.Close()
+	if got := d.m.ToMap(); len(got) != 1 || got["c"] != 3 {
+		t.Fatalf("expected map[c:3], got %v", got)
+	}
+}
@@ -0,0 +1,195 @@
+package maps
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Both map types encode as a plain JSON object or gob map. JSON supports
+// keys of string and integer kinds and keys implementing
+// encoding.TextMarshaler/TextUnmarshaler, as encoding/json does for Go maps.
+//
+// Decoding merges the decoded entries into the map, like encoding/json and
+// encoding/gob do for Go maps; existing entries with other keys are kept.
+
+func (t *TypedSyncMap[K, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.ToMap())
+}
+
+func (t *TypedSyncMap[K, V]) UnmarshalJSON(data []byte) error {
+	var src map[K]V
+	if err := json.Unmarshal(data, &src); err != nil {
+		return err
+	}
+	t.storeAll(src)
+	return nil
+}
+
+func (t *TypedSyncMap[K, V]) GobEncode() ([]byte, error) {
+	return gobEncode(t.ToMap())
+}
+
+func (t *TypedSyncMap[K, V]) GobDecode(data []byte) error {
+	var src map[K]V
+	if err := gobDecode(data, &src); err != nil {
+		return err
+	}
+	t.storeAll(src)
+	return nil
+}
+
+func (t *TypedSyncMap[K, V]) storeAll(src map[K]V) {
+	if t.m == nil {
+		t.m = new(sync.Map)
+	}
+	for k, v := range src {
+		t.Store(k, v)
+	}
+}
+
+// ttlWireEntry is the encoded form of an entry when remaining TTL
+// encoding is enabled. TTL is in nanoseconds.
+type ttlWireEntry[V any] struct {
+	Value V             `json:"value"`
+	TTL   time.Duration `json:"ttl"`
+}
+
+// EncodeRemainingTTL controls the encoding format of the map. By default
+// entries are encoded as plain key/value pairs and decoded entries get a
+// fresh expiration. When enabled, every value is wrapped together with its
+// remaining TTL, e.g. {"1":{"value":"foo","ttl":1500000000}}, and decoding
+// restores that TTL. Encoder and decoder must use the same setting.
+//
+// Encoding neither prolongs TTL nor includes expired entries.
+func (t *TtlTypedSyncMap[K, V]) EncodeRemainingTTL(enabled bool) {
+	t.encodeTTL.Store(enabled)
+}
+
+func (t *TtlTypedSyncMap[K, V]) MarshalJSON() ([]byte, error) {
+	if t.encodeTTL.Load() {
+		return json.Marshal(t.wireEntries())
+	}
+	return json.Marshal(t.plainEntries())
+}
+
+func (t *TtlTypedSyncMap[K, V]) UnmarshalJSON(data []byte) error {
+	if t.encodeTTL.Load() {
+		var src map[K]ttlWireEntry[V]
+		if err := json.Unmarshal(data, &src); err != nil {
+			return err
+		}
+		t.storeWireEntries(src)
+		return nil
+	}
+
+	var src map[K]V
+	if err := json.Unmarshal(data, &src); err != nil {
+		return err
+	}
+	t.storePlainEntries(src)
+	return nil
+}
+
+func (t *TtlTypedSyncMap[K, V]) GobEncode() ([]byte, error) {
+	if t.encodeTTL.Load() {
+		return gobEncode(t.wireEntries())
+	}
+	return gobEncode(t.plainEntries())
+}
+
+func (t *TtlTypedSyncMap[K, V]) GobDecode(data []byte) error {
+	if t.encodeTTL.Load() {
+		var src map[K]ttlWireEntry[V]
+		if err := gobDecode(data, &src); err != nil {
+			return err
+		}
+		t.storeWireEntries(src)
+		return nil
+	}
+
+	var src map[K]V
+	if err := gobDecode(data, &src); err != nil {
+		return err
+	}
+	t.storePlainEntries(src)
+	return nil
+}
+
+func (t *TtlTypedSyncMap[K, V]) plainEntries() map[K]V {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	res := make(map[K]V, len(t.items))
+	for k, entry := range t.items {
+		if !entry.expired(now) {
+			res[k] = entry.value
+		}
+	}
+	return res
+}
+
+func (t *TtlTypedSyncMap[K, V]) wireEntries() map[K]ttlWireEntry[V] {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	res := make(map[K]ttlWireEntry[V], len(t.items))
+	for k, entry := range t.items {
+		if !entry.expired(now) {
+			res[k] = ttlWireEntry[V]{Value: entry.value, TTL: entry.expiresAt.Sub(now)}
+		}
+	}
+	return res
+}
+
+func (t *TtlTypedSyncMap[K, V]) storePlainEntries(src map[K]V) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.initDecoded()
+	now := time.Now()
+	for k, v := range src {
+		t.items[k] = newTTLEntry(v, now, t.expDuration)
+	}
+}
+
+func (t *TtlTypedSyncMap[K, V]) storeWireEntries(src map[K]ttlWireEntry[V]) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.initDecoded()
+	now := time.Now()
+	for k, e := range src {
+		if e.TTL <= 0 {
+			continue
+		}
+		t.items[k] = newTTLEntry(e.Value, now, e.TTL)
+	}
+}
+
+// initDecoded makes a zero TtlTypedSyncMap usable as a decoding target.
+// Such a map has no janitor; expired entries are only removed on access.
+func (t *TtlTypedSyncMap[K, V]) initDecoded() {
+	if t.items == nil {
+		t.items = make(map[K]ttlEntry[V])
+	}
+	if t.expDuration <= 0 {
+		t.expDuration = defaultTTL
+	}
+}
+
+func gobEncode(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gobDecode(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
@@ -0,0 +1,215 @@
+package maps
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// point is a non-string key encoded through encoding.TextMarshaler.
+type point struct {
+	X, Y int
+}
+
+func (p point) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d:%d", p.X, p.Y)), nil
+}
+
+func (p *point) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "%d:%d", &p.X, &p.Y)
+	return err
+}
+
+func TestTypedSyncMap_JSON(t *testing.T) {
+	m := NewTypedSyncMap[int, string]()
+	m.Store(1, "a")
+	m.Store(2, "b")
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(data) != `{"1":"a","2":"b"}` {
+		t.Fatalf("unexpected JSON %s", data)
+	}
+
+	var decoded TypedSyncMap[int, string]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if decoded.Len() != 2 {
+		t.Fatalf("expected len 2, got %d", decoded.Len())
+	}
+	if v, ok := decoded.Load(2); !ok || v != "b" {
+		t.Fatalf("expected (\"b\", true), got (%v, %v)", v, ok)
+	}
+}
+
+func TestTypedSyncMap_JSON_TextMarshalerKeys(t *testing.T) {
+	m := NewTypedSyncMap[point, int]()
+	m.Store(point{1, 2}, 3)
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(data) != `{"1:2":3}` {
+		t.Fatalf("unexpected JSON %s", data)
+	}
+
+	decoded := NewTypedSyncMap[point, int]()
+	decoded.Store(point{0, 0}, 0)
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if v, ok := decoded.Load(point{1, 2}); !ok || v != 3 {
+		t.Fatalf("expected (3, true), got (%v, %v)", v, ok)
+	}
+	if decoded.Len() != 2 {
+		t.Fatalf("expected decoding to merge into existing entries, got len %d", decoded.Len())
+	}
+}
+
+func TestTypedSyncMap_JSON_InvalidInput(t *testing.T) {
+	m := NewTypedSyncMap[int, string]()
+	if err := json.Unmarshal([]byte(`{"x":"a"}`), m); err == nil {
+		t.Fatal("expected error for non-integer key")
+	}
+	if m.Len() != 0 {
+		t.Fatalf("expected no entries after failed decode, got %d", m.Len())
+	}
+}
+
+func TestTypedSyncMap_Gob(t *testing.T) {
+	m := NewTypedSyncMap[point, string]()
+	m.Store(point{1, 2}, "a")
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(m); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	decoded := NewTypedSyncMap[point, string]()
+	if err := gob.NewDecoder(&buf).Decode(decoded); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if v, ok := decoded.Load(point{1, 2}); !ok || v != "a" {
+		t.Fatalf("expected (\"a\", true), got (%v, %v)", v, ok)
+	}
+	if err := decoded.GobDecode([]byte("garbage")); err == nil {
+		t.Fatal("expected error for invalid gob data")
+	}
+}
+
+func TestTtlTypedSyncMap_JSON_Plain(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewTtlTypedSyncMap[string, int](ctx, time.Minute, time.Minute)
+	m.Store("a", 1)
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(data) != `{"a":1}` {
+		t.Fatalf("unexpected JSON %s", data)
+	}
+
+	decoded := NewTtlTypedSyncMap[string, int](ctx, time.Minute, time.Minute)
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if v, ok := decoded.Load("a"); !ok || v != 1 {
+		t.Fatalf("expected (1, true), got (%v, %v)", v, ok)
+	}
+	if err := json.Unmarshal([]byte(`[]`), decoded); err == nil {
+		t.Fatal("expected error for invalid JSON")
+	}
+}
+
+func TestTtlTypedSyncMap_JSON_RemainingTTL(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewTtlTypedSyncMap[string, int](ctx, time.Minute, time.Minute)
+	m.EncodeRemainingTTL(true)
+	m.Store("a", 1)
+	m.mu.Lock()
+	m.items["expired"] = ttlEntry[int]{value: 2, expiresAt: time.Now().Add(-time.Second)}
+	m.mu.Unlock()
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var raw map[string]ttlWireEntry[int]
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("unexpected JSON %s: %v", data, err)
+	}
+	if len(raw) != 1 || raw["a"].Value != 1 {
+		t.Fatalf("expected only the live entry, got %s", data)
+	}
+	if ttl := raw["a"].TTL; ttl <= 0 || ttl > time.Minute {
+		t.Fatalf("expected remaining TTL within (0, 1m], got %v", ttl)
+	}
+
+	// decoding restores the encoded TTL rather than the target's expDuration
+	decoded := NewTtlTypedSyncMap[string, int](ctx, time.Millisecond, time.Minute)
+	decoded.EncodeRemainingTTL(true)
+	payload := []byte(`{"a":{"value":1,"ttl":60000000000},"gone":{"value":2,"ttl":0}}`)
+	if err := json.Unmarshal(payload, decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if decoded.Len() != 1 {
+		t.Fatalf("expected only entries with positive TTL, got len %d", decoded.Len())
+	}
+	time.Sleep(5 * time.Millisecond)
+	if v, ok := decoded.Load("a"); !ok || v != 1 {
+		t.Fatalf("expected entry to outlive the 1ms expDuration, got (%v, %v)", v, ok)
+	}
+	if err := json.Unmarshal([]byte(`{"a":1}`), decoded); err == nil {
+		t.Fatal("expected error for plain payload in TTL mode")
+	}
+}
+
+func TestTtlTypedSyncMap_Gob(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, withTTL := range []bool{false, true} {
+		m := NewTtlTypedSyncMap[point, string](ctx, time.Minute, time.Minute)
+		m.EncodeRemainingTTL(withTTL)
+		m.Store(point{1, 2}, "a")
+
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(m); err != nil {
+			t.Fatalf("encode (ttl=%v): %v", withTTL, err)
+		}
+		decoded := NewTtlTypedSyncMap[point, string](ctx, time.Minute, time.Minute)
+		decoded.EncodeRemainingTTL(withTTL)
+		if err := gob.NewDecoder(&buf).Decode(decoded); err != nil {
+			t.Fatalf("decode (ttl=%v): %v", withTTL, err)
+		}
+		if v, ok := decoded.Load(point{1, 2}); !ok || v != "a" {
+			t.Fatalf("expected (\"a\", true) (ttl=%v), got (%v, %v)", withTTL, v, ok)
+		}
+		if err := decoded.GobDecode([]byte("garbage")); err == nil {
+			t.Fatalf("expected error for invalid gob data (ttl=%v)", withTTL)
+		}
+	}
+}
+
+func TestTtlTypedSyncMap_Unmarshal_ZeroValue(t *testing.T) {
+	var m TtlTypedSyncMap[string, int]
+	if err := json.Unmarshal([]byte(`{"a":1}`), &m); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if v, ok := m.Load("a"); !ok || v != 1 {
+		t.Fatalf("expected (1, true), got (%v, %v)", v, ok)
+	}
+	if m.expDuration != time.Second {
+		t.Fatalf("expected default expDuration of 1s, got %v", m.expDuration)
+	}
+}
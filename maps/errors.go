@@ -0,0 +1,19 @@
+package maps
+
+import "errors"
+
+var (
+	ErrTypeMismatch = errors.New("sync.Map entry has unexpected key or value type")
+	ErrValueExists  = errors.New("value is already mapped to another key")
+	ErrTxnConflict  = errors.New("transaction conflicts with a concurrent write")
+
+	ErrDurableMapClosed = errors.New("durable map is closed")
+	ErrDurableMapFailed = errors.New("durable map log is in an unknown state after a failed write")
+
+	ErrIndexExists   = errors.New("index already exists")
+	ErrIndexNotFound = errors.New("index not found")
+
+	ErrMerkleDepthMismatch = errors.New("merkle trees have different depths")
+	ErrMerkleLevel         = errors.New("merkle node index out of range")
+	ErrMerkleHashCount     = errors.New("merkle peer returned unexpected number of hashes")
+)
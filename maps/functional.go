@@ -0,0 +1,86 @@
+package maps
+
+import "iter"
+
+// The helpers below work over any map of this package through Ranger.
+// They inherit the source's Range semantics: for example, ranging a
+// TtlTypedSyncMap prolongs the entries it visits.
+
+// Filter returns a new TypedSyncMap with the entries of src for which keep
+// returns true.
+func Filter[K comparable, V any](src Ranger[K, V], keep func(key K, value V) bool) *TypedSyncMap[K, V] {
+	return Collect(FilterSeq(Seq(src), keep))
+}
+
+// MapValues returns a new TypedSyncMap with the keys of src mapped to fn of
+// their values.
+func MapValues[K comparable, V any, R any](src Ranger[K, V], fn func(key K, value V) R) *TypedSyncMap[K, R] {
+	return Collect(MapValuesSeq(Seq(src), fn))
+}
+
+// Reduce folds the entries of src into an accumulator starting at init.
+// Entries are visited in src's Range order.
+func Reduce[K comparable, V any, A any](src Ranger[K, V], init A, fn func(acc A, key K, value V) A) A {
+	acc := init
+	src.Range(func(key K, value V) bool {
+		acc = fn(acc, key, value)
+		return true
+	})
+	return acc
+}
+
+// GroupBy partitions the entries of src by the group fn assigns to them.
+func GroupBy[K comparable, V any, G comparable](src Ranger[K, V], fn func(key K, value V) G) map[G]map[K]V {
+	groups := make(map[G]map[K]V)
+	src.Range(func(key K, value V) bool {
+		g := fn(key, value)
+		group, ok := groups[g]
+		if !ok {
+			group = make(map[K]V)
+			groups[g] = group
+		}
+		group[key] = value
+		return true
+	})
+	return groups
+}
+
+// Seq returns an iterator over the entries of src. Each iteration calls
+// src.Range, so the sequence can be consumed more than once.
+func Seq[K comparable, V any](src Ranger[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		src.Range(yield)
+	}
+}
+
+// FilterSeq lazily yields the entries of seq for which keep returns true.
+func FilterSeq[K comparable, V any](seq iter.Seq2[K, V], keep func(key K, value V) bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range seq {
+			if keep(k, v) && !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// MapValuesSeq lazily yields the keys of seq with fn of their values.
+func MapValuesSeq[K comparable, V any, R any](seq iter.Seq2[K, V], fn func(key K, value V) R) iter.Seq2[K, R] {
+	return func(yield func(K, R) bool) {
+		for k, v := range seq {
+			if !yield(k, fn(k, v)) {
+				return
+			}
+		}
+	}
+}
+
+// Collect stores the entries of seq in a new TypedSyncMap. Later entries
+// overwrite earlier ones with the same key.
+func Collect[K comparable, V any](seq iter.Seq2[K, V]) *TypedSyncMap[K, V] {
+	res := NewTypedSyncMap[K, V]()
+	for k, v := range seq {
+		res.Store(k, v)
+	}
+	return res
+}
@@ -0,0 +1,93 @@
+package maps
+
+import (
+	stdmaps "maps"
+	"strconv"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	src := NewFromMap(map[string]int{"a": 1, "b": 2, "c": 3, "d": 4})
+	even := Filter(src, func(k string, v int) bool { return v%2 == 0 })
+	if got := even.ToMap(); !stdmaps.Equal(got, map[string]int{"b": 2, "d": 4}) {
+		t.Fatalf("expected map[b:2 d:4], got %v", got)
+	}
+	if src.Len() != 4 {
+		t.Fatalf("expected source to be unchanged, got len %d", src.Len())
+	}
+}
+
+func TestMapValues(t *testing.T) {
+	src := NewSortedMap[int, int]()
+	src.Store(1, 10)
+	src.Store(2, 20)
+	got := MapValues(src, func(k int, v int) string { return strconv.Itoa(k + v) }).ToMap()
+	if !stdmaps.Equal(got, map[int]string{1: "11", 2: "22"}) {
+		t.Fatalf("expected map[1:11 2:22], got %v", got)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	src := NewOrderedMap[string, int]()
+	src.Store("a", 1)
+	src.Store("b", 2)
+	src.Store("c", 3)
+	if sum := Reduce(src, 0, func(acc int, k string, v int) int { return acc + v }); sum != 6 {
+		t.Fatalf("expected 6, got %d", sum)
+	}
+	// entries are visited in the source's order
+	keys := Reduce(src, "", func(acc string, k string, v int) string { return acc + k })
+	if keys != "abc" {
+		t.Fatalf("expected \"abc\", got %q", keys)
+	}
+	empty := NewTypedSyncMap[string, int]()
+	if got := Reduce(empty, 42, func(acc int, k string, v int) int { return 0 }); got != 42 {
+		t.Fatalf("expected init for empty map, got %d", got)
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	src := NewFromMap(map[string]int{"a": 1, "b": 2, "c": 3})
+	groups := GroupBy(src, func(k string, v int) bool { return v%2 == 1 })
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %v", groups)
+	}
+	if !stdmaps.Equal(groups[true], map[string]int{"a": 1, "c": 3}) || !stdmaps.Equal(groups[false], map[string]int{"b": 2}) {
+		t.Fatalf("unexpected groups %v", groups)
+	}
+}
+
+func TestSeq_LazyAndStoppable(t *testing.T) {
+	src := NewSortedMap[int, int]()
+	for i := 0; i < 10; i++ {
+		src.Store(i, i)
+	}
+
+	calls := 0
+	seq := MapValuesSeq(FilterSeq(Seq(src), func(k, v int) bool {
+		calls++
+		return v%2 == 0
+	}), func(k, v int) int { return v * 10 })
+	if calls != 0 {
+		t.Fatal("expected building the pipeline not to iterate")
+	}
+
+	var got []int
+	for _, v := range seq {
+		got = append(got, v)
+		if len(got) == 2 {
+			break
+		}
+	}
+	if len(got) != 2 || got[0] != 0 || got[1] != 20 {
+		t.Fatalf("expected [0 20], got %v", got)
+	}
+	if calls != 3 {
+		t.Fatalf("expected iteration to stop early after 3 predicate calls, got %d", calls)
+	}
+
+	// the sequence can be consumed again
+	if n := Collect(seq).Len(); n != 5 {
+		t.Fatalf("expected 5 collected entries, got %d", n)
+	}
+}
@@ -0,0 +1,87 @@
+package maps
+
+import (
+	"cmp"
+	"sync"
+	"time"
+)
+
+// HLCTimestamp is a hybrid logical clock timestamp. Timestamps are totally
+// ordered by wall time, then logical counter, then node ID.
+type HLCTimestamp struct {
+	WallTime int64  `json:"wall"`
+	Logical  uint32 `json:"logical"`
+	NodeID   string `json:"node"`
+}
+
+// Compare returns -1, 0 or +1 depending on whether t is before, equal to
+// or after other.
+func (t HLCTimestamp) Compare(other HLCTimestamp) int {
+	if c := cmp.Compare(t.WallTime, other.WallTime); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(t.Logical, other.Logical); c != 0 {
+		return c
+	}
+	return cmp.Compare(t.NodeID, other.NodeID)
+}
+
+// HybridClock issues hybrid logical clock timestamps for one node: they
+// follow physical time when it advances and stay monotonic and causally
+// ordered when it does not or when clocks of other nodes run ahead.
+// Safe for concurrent use.
+type HybridClock struct {
+	mu     sync.Mutex
+	nodeID string
+	now    func() time.Time
+	last   HLCTimestamp
+}
+
+// NewHybridClock returns a clock for nodeID reading physical time from now;
+// if now is nil, time.Now is used.
+func NewHybridClock(nodeID string, now func() time.Time) *HybridClock {
+	if now == nil {
+		now = time.Now
+	}
+	return &HybridClock{
+		nodeID: nodeID,
+		now:    now,
+		last:   HLCTimestamp{NodeID: nodeID},
+	}
+}
+
+// Now returns a timestamp for a local event, greater than every timestamp
+// previously issued or observed by the clock.
+func (c *HybridClock) Now() HLCTimestamp {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pt := c.now().UnixNano()
+	if pt > c.last.WallTime {
+		c.last.WallTime = pt
+		c.last.Logical = 0
+	} else {
+		c.last.Logical++
+	}
+	return c.last
+}
+
+// Observe advances the clock past a timestamp received from another node.
+func (c *HybridClock) Observe(remote HLCTimestamp) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pt := c.now().UnixNano()
+	wall := max(c.last.WallTime, remote.WallTime, pt)
+	switch {
+	case wall == c.last.WallTime && wall == remote.WallTime:
+		c.last.Logical = max(c.last.Logical, remote.Logical) + 1
+	case wall == c.last.WallTime:
+		c.last.Logical++
+	case wall == remote.WallTime:
+		c.last.Logical = remote.Logical + 1
+	default:
+		c.last.Logical = 0
+	}
+	c.last.WallTime = wall
+}
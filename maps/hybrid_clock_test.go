@@ -0,0 +1,103 @@
+package maps
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced time source.
+type fakeClock struct {
+	t time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.t
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.t = f.t.Add(d)
+}
+
+func TestHLCTimestamp_Compare(t *testing.T) {
+	cases := []struct {
+		a, b HLCTimestamp
+		want int
+	}{
+		{HLCTimestamp{1, 0, "a"}, HLCTimestamp{2, 0, "a"}, -1},
+		{HLCTimestamp{2, 0, "a"}, HLCTimestamp{1, 5, "a"}, 1},
+		{HLCTimestamp{1, 1, "a"}, HLCTimestamp{1, 2, "a"}, -1},
+		{HLCTimestamp{1, 1, "b"}, HLCTimestamp{1, 1, "a"}, 1},
+		{HLCTimestamp{1, 1, "a"}, HLCTimestamp{1, 1, "a"}, 0},
+	}
+	for _, c := range cases {
+		if got := c.a.Compare(c.b); got != c.want {
+			t.Errorf("%v.Compare(%v) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestHybridClock_MonotonicWhenTimeStalls(t *testing.T) {
+	fc := &fakeClock{t: time.Unix(100, 0)}
+	c := NewHybridClock("n1", fc.Now)
+
+	t1 := c.Now()
+	t2 := c.Now()
+	if t2.Compare(t1) <= 0 || t2.WallTime != t1.WallTime || t2.Logical != 1 {
+		t.Fatalf("expected logical increment, got %v then %v", t1, t2)
+	}
+
+	fc.Advance(time.Second)
+	t3 := c.Now()
+	if t3.Logical != 0 || t3.WallTime != fc.t.UnixNano() {
+		t.Fatalf("expected clock to follow physical time, got %v", t3)
+	}
+
+	fc.Advance(-time.Minute) // physical clock goes backwards
+	if t4 := c.Now(); t4.Compare(t3) <= 0 {
+		t.Fatalf("expected monotonic timestamps, got %v after %v", t4, t3)
+	}
+}
+
+func TestHybridClock_Observe(t *testing.T) {
+	fc := &fakeClock{t: time.Unix(100, 0)}
+	c := NewHybridClock("n1", fc.Now)
+	local := c.Now()
+
+	// remote clock runs ahead
+	remote := HLCTimestamp{WallTime: local.WallTime + 1000, Logical: 7, NodeID: "n2"}
+	c.Observe(remote)
+	if next := c.Now(); next.Compare(remote) <= 0 {
+		t.Fatalf("expected timestamps after observed remote %v, got %v", remote, next)
+	}
+
+	// equal wall times take the larger logical counter
+	c2 := NewHybridClock("n3", fc.Now)
+	c2.Now()
+	c2.Observe(HLCTimestamp{WallTime: fc.t.UnixNano(), Logical: 4, NodeID: "n2"})
+	if got := c2.Now(); got.Logical != 6 {
+		t.Fatalf("expected logical 6, got %v", got)
+	}
+
+	// an old remote timestamp still advances the logical counter
+	c3 := NewHybridClock("n4", fc.Now)
+	before := c3.Now()
+	c3.Observe(HLCTimestamp{WallTime: 1, NodeID: "n2"})
+	if got := c3.Now(); got.Compare(before) <= 0 {
+		t.Fatalf("expected %v to be after %v", got, before)
+	}
+
+	// physical time ahead of both resets the logical counter
+	fc.Advance(time.Hour)
+	c3.Observe(HLCTimestamp{WallTime: 1, NodeID: "n2"})
+	if got := c3.Now(); got.WallTime != fc.t.UnixNano() || got.Logical != 1 {
+		t.Fatalf("expected physical time with logical 1, got %v", got)
+	}
+}
+
+func TestNewHybridClock_DefaultsToTimeNow(t *testing.T) {
+	c := NewHybridClock("n1", nil)
+	before := time.Now().UnixNano()
+	if ts := c.Now(); ts.WallTime < before || ts.NodeID != "n1" {
+		t.Fatalf("expected wall time from time.Now and node n1, got %v", ts)
+	}
+}
@@ -0,0 +1,243 @@
+package maps
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// IndexFunc returns the index keys of a value. A value may have any number
+// of index keys, including none.
+type IndexFunc[V any, IK comparable] func(value V) []IK
+
+// IndexedMap is a concurrent map with named secondary indexes: values can be
+// looked up by the keys an IndexFunc derives from them instead of scanning
+// the whole map. Indexes are updated on every Store and Delete, and on
+// expiry for maps created by NewTtlIndexedMap. Safe for concurrent use.
+//
+// Index functions are called under the map's lock and must not call back
+// into the map. They must be deterministic: the keys of a replaced or
+// removed value are recomputed to drop it from the indexes.
+type IndexedMap[K comparable, V any, IK comparable] struct {
+	mu          sync.Mutex
+	items       map[K]ttlEntry[V]
+	indexes     map[string]*secondaryIndex[K, V, IK]
+	expDuration time.Duration // zero disables expiry
+}
+
+type secondaryIndex[K comparable, V any, IK comparable] struct {
+	fn      IndexFunc[V, IK]
+	entries map[IK]map[K]struct{}
+}
+
+// NewIndexedMap returns a new empty IndexedMap without expiry.
+func NewIndexedMap[K comparable, V any, IK comparable]() *IndexedMap[K, V, IK] {
+	return &IndexedMap[K, V, IK]{
+		items:   make(map[K]ttlEntry[V]),
+		indexes: make(map[string]*secondaryIndex[K, V, IK]),
+	}
+}
+
+// NewTtlIndexedMap returns a new empty IndexedMap with sliding expiration,
+// like TtlTypedSyncMap: Load, Range and Lookup prolong the entries they
+// return. Expired entries are removed from the map and its indexes by a
+// janitor running until ctx is done.
+func NewTtlIndexedMap[K comparable, V any, IK comparable](
+	ctx context.Context,
+	expDuration time.Duration,
+	sanitizeInterval time.Duration,
+) *IndexedMap[K, V, IK] {
+	expDuration, sanitizeInterval = ttlDurations(expDuration, sanitizeInterval)
+	res := NewIndexedMap[K, V, IK]()
+	res.expDuration = expDuration
+	go runJanitor(ctx, sanitizeInterval, res.sweep)
+	return res
+}
+
+// AddIndex registers an index under name and builds it from the current
+// entries. It returns ErrIndexExists if name is already registered.
+func (m *IndexedMap[K, V, IK]) AddIndex(name string, fn IndexFunc[V, IK]) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.indexes[name]; ok {
+		return ErrIndexExists
+	}
+	idx := &secondaryIndex[K, V, IK]{
+		fn:      fn,
+		entries: make(map[IK]map[K]struct{}),
+	}
+	now := time.Now()
+	for k, entry := range m.items {
+		if m.expired(entry, now) {
+			m.removeLocked(k)
+			continue
+		}
+		idx.add(k, entry.value)
+	}
+	m.indexes[name] = idx
+	return nil
+}
+
+// Lookup returns the values whose index keys under the named index include
+// ik, in no particular order. It returns ErrIndexNotFound if no index is
+// registered under name.
+func (m *IndexedMap[K, V, IK]) Lookup(name string, ik IK) ([]V, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	idx, ok := m.indexes[name]
+	if !ok {
+		return nil, ErrIndexNotFound
+	}
+	keys := idx.entries[ik]
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	values := make([]V, 0, len(keys))
+	for k := range keys {
+		entry := m.items[k]
+		if m.expired(entry, now) {
+			// deleting from the set being ranged over is safe
+			m.removeLocked(k)
+			continue
+		}
+		m.touch(k, entry, now)
+		values = append(values, entry.value)
+	}
+	return values, nil
+}
+
+func (m *IndexedMap[K, V, IK]) Store(key K, value V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.removeLocked(key)
+	entry := ttlEntry[V]{value: value}
+	if m.expDuration > 0 {
+		entry = newTTLEntry(value, time.Now(), m.expDuration)
+	}
+	m.items[key] = entry
+	for _, idx := range m.indexes {
+		idx.add(key, value)
+	}
+}
+
+func (m *IndexedMap[K, V, IK]) Load(key K) (V, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	now := time.Now()
+	if m.expired(entry, now) {
+		m.removeLocked(key)
+		var zero V
+		return zero, false
+	}
+	m.touch(key, entry, now)
+	return entry.value, true
+}
+
+func (m *IndexedMap[K, V, IK]) Delete(key K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.removeLocked(key)
+}
+
+func (m *IndexedMap[K, V, IK]) Len() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return int64(len(m.items))
+}
+
+// Range calls f for each entry until f returns false.
+// It iterates over a copy taken under the lock, so f may modify the map.
+func (m *IndexedMap[K, V, IK]) Range(f func(key K, value V) bool) {
+	if f == nil {
+		return
+	}
+
+	m.mu.Lock()
+	now := time.Now()
+	keys := make([]K, 0, len(m.items))
+	values := make([]V, 0, len(m.items))
+	for k, entry := range m.items {
+		if m.expired(entry, now) {
+			m.removeLocked(k)
+			continue
+		}
+		m.touch(k, entry, now)
+		keys = append(keys, k)
+		values = append(values, entry.value)
+	}
+	m.mu.Unlock()
+	rangeCollected(keys, values, f)
+}
+
+// TTL returns the sliding expiration period, or zero for a map created
+// by NewIndexedMap, whose entries never expire.
+func (m *IndexedMap[K, V, IK]) TTL() time.Duration {
+	return m.expDuration
+}
+
+func (m *IndexedMap[K, V, IK]) expired(entry ttlEntry[V], now time.Time) bool {
+	return m.expDuration > 0 && entry.expired(now)
+}
+
+// touch prolongs the entry of key when expiry is enabled. Callers must hold m.mu.
+func (m *IndexedMap[K, V, IK]) touch(key K, entry ttlEntry[V], now time.Time) {
+	if m.expDuration > 0 {
+		m.items[key] = entry.prolonged(now, m.expDuration)
+	}
+}
+
+// removeLocked deletes key from the map and its indexes. Callers must hold m.mu.
+func (m *IndexedMap[K, V, IK]) removeLocked(key K) {
+	entry, ok := m.items[key]
+	if !ok {
+		return
+	}
+	delete(m.items, key)
+	for _, idx := range m.indexes {
+		idx.remove(key, entry.value)
+	}
+}
+
+// sweep removes the entries expired at now from the map and its indexes.
+func (m *IndexedMap[K, V, IK]) sweep(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for k, entry := range m.items {
+		if m.expired(entry, now) {
+			m.removeLocked(k)
+		}
+	}
+}
+
+func (idx *secondaryIndex[K, V, IK]) add(key K, value V) {
+	for _, ik := range idx.fn(value) {
+		keys, ok := idx.entries[ik]
+		if !ok {
+			keys = make(map[K]struct{})
+			idx.entries[ik] = keys
+		}
+		keys[key] = struct{}{}
+	}
+}
+
+func (idx *secondaryIndex[K, V, IK]) remove(key K, value V) {
+	for _, ik := range idx.fn(value) {
+		keys := idx.entries[ik]
+		delete(keys, key)
+		if len(keys) == 0 {
+			delete(idx.entries, ik)
+		}
+	}
+}
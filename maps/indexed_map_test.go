@@ -0,0 +1,208 @@
+package maps
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type user struct {
+	name  string
+	email string
+	tags  []string
+}
+
+func byEmail(u user) []string { return []string{u.email} }
+func byTag(u user) []string   { return u.tags }
+
+func lookupNames(t *testing.T, m *IndexedMap[int, user, string], index, ik string) []string {
+	t.Helper()
+	values, err := m.Lookup(index, ik)
+	if err != nil {
+		t.Fatalf("unexpected lookup error: %v", err)
+	}
+	names := make([]string, 0, len(values))
+	for _, u := range values {
+		names = append(names, u.name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestIndexedMap_Lookup(t *testing.T) {
+	m := NewIndexedMap[int, user, string]()
+	if err := m.AddIndex("email", byEmail); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := m.AddIndex("tag", byTag); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m.Store(1, user{name: "ann", email: "ann@x", tags: []string{"admin", "dev"}})
+	m.Store(2, user{name: "bob", email: "bob@x", tags: []string{"dev"}})
+	m.Store(3, user{name: "eve", email: "eve@x"})
+
+	if got := lookupNames(t, m, "email", "bob@x"); !slices.Equal(got, []string{"bob"}) {
+		t.Fatalf("expected [bob], got %v", got)
+	}
+	if got := lookupNames(t, m, "tag", "dev"); !slices.Equal(got, []string{"ann", "bob"}) {
+		t.Fatalf("expected [ann bob], got %v", got)
+	}
+	if got := lookupNames(t, m, "tag", "missing"); len(got) != 0 {
+		t.Fatalf("expected no values, got %v", got)
+	}
+}
+
+func TestIndexedMap_StoreAndDeleteKeepIndexesConsistent(t *testing.T) {
+	m := NewIndexedMap[int, user, string]()
+	m.AddIndex("email", byEmail)
+	m.Store(1, user{name: "ann", email: "old@x"})
+	m.Store(1, user{name: "ann", email: "new@x"})
+
+	if got := lookupNames(t, m, "email", "old@x"); len(got) != 0 {
+		t.Fatalf("expected replaced value to leave the index, got %v", got)
+	}
+	if got := lookupNames(t, m, "email", "new@x"); !slices.Equal(got, []string{"ann"}) {
+		t.Fatalf("expected [ann], got %v", got)
+	}
+
+	m.Delete(1)
+	m.Delete(42)
+	if got := lookupNames(t, m, "email", "new@x"); len(got) != 0 {
+		t.Fatalf("expected deleted value to leave the index, got %v", got)
+	}
+	if len(m.indexes["email"].entries) != 0 {
+		t.Fatalf("expected empty index buckets to be dropped, got %v", m.indexes["email"].entries)
+	}
+}
+
+func TestIndexedMap_AddIndexBuildsFromExistingEntries(t *testing.T) {
+	m := NewIndexedMap[int, user, string]()
+	m.Store(1, user{name: "ann", email: "ann@x"})
+	m.AddIndex("email", byEmail)
+	if got := lookupNames(t, m, "email", "ann@x"); !slices.Equal(got, []string{"ann"}) {
+		t.Fatalf("expected [ann], got %v", got)
+	}
+}
+
+func TestIndexedMap_Errors(t *testing.T) {
+	m := NewIndexedMap[int, user, string]()
+	m.AddIndex("email", byEmail)
+	if err := m.AddIndex("email", byTag); !errors.Is(err, ErrIndexExists) {
+		t.Fatalf("expected ErrIndexExists, got %v", err)
+	}
+	if _, err := m.Lookup("missing", "x"); !errors.Is(err, ErrIndexNotFound) {
+		t.Fatalf("expected ErrIndexNotFound, got %v", err)
+	}
+}
+
+func TestIndexedMap_Range(t *testing.T) {
+	m := NewIndexedMap[int, user, string]()
+	m.AddIndex("email", byEmail)
+	m.Store(1, user{email: "a"})
+	m.Store(2, user{email: "b"})
+
+	seen := 0
+	m.Range(func(k int, v user) bool {
+		m.Delete(k) // modifying the map from f must not deadlock
+		seen++
+		return true
+	})
+	if seen != 2 || m.Len() != 0 {
+		t.Fatalf("expected to visit and delete 2 entries, visited %d, len %d", seen, m.Len())
+	}
+
+	m.Store(1, user{})
+	m.Store(2, user{})
+	times := 0
+	m.Range(func(k int, v user) bool {
+		times++
+		return false
+	})
+	if times != 1 {
+		t.Fatalf("expected Range to stop after first iteration, got %d iterations", times)
+	}
+	m.Range(nil)
+}
+
+func TestTtlIndexedMap_ExpiryUpdatesIndexes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewTtlIndexedMap[int, user, string](ctx, 20*time.Millisecond, 5*time.Millisecond)
+	m.AddIndex("email", byEmail)
+	m.Store(1, user{name: "ann", email: "ann@x"})
+
+	time.Sleep(60 * time.Millisecond)
+	if m.Len() != 0 {
+		t.Fatalf("expected janitor to remove expired entry, got len %d", m.Len())
+	}
+	m.mu.Lock()
+	buckets := len(m.indexes["email"].entries)
+	m.mu.Unlock()
+	if buckets != 0 {
+		t.Fatalf("expected expired entry to leave the index, got %d buckets", buckets)
+	}
+}
+
+func TestIndexedMap_TTL(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if ttl := NewTtlIndexedMap[int, user, string](ctx, time.Minute, 0).TTL(); ttl != time.Minute {
+		t.Fatalf("expected one-minute TTL, got %v", ttl)
+	}
+	if ttl := NewIndexedMap[int, user, string]().TTL(); ttl != 0 {
+		t.Fatalf("expected zero TTL without expiry, got %v", ttl)
+	}
+}
+
+func TestTtlIndexedMap_LookupSkipsExpiredAndProlongs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// the janitor interval is long enough not to interfere
+	m := NewTtlIndexedMap[int, user, string](ctx, 50*time.Millisecond, time.Hour)
+	m.AddIndex("tag", byTag)
+	m.Store(1, user{name: "ann", tags: []string{"dev"}})
+	m.Store(2, user{name: "bob", tags: []string{"dev"}})
+
+	for i := 0; i < 4; i++ {
+		time.Sleep(20 * time.Millisecond)
+		if _, ok := m.Load(1); !ok {
+			t.Fatal("expected Load to prolong the entry")
+		}
+	}
+	if got := lookupNames(t, m, "tag", "dev"); !slices.Equal(got, []string{"ann"}) {
+		t.Fatalf("expected only the prolonged entry, got %v", got)
+	}
+	if m.Len() != 1 {
+		t.Fatalf("expected expired entry to be removed by Lookup, got len %d", m.Len())
+	}
+}
+
+func TestIndexedMap_Concurrent(t *testing.T) {
+	m := NewIndexedMap[int, user, string]()
+	m.AddIndex("email", byEmail)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			m.Store(i%10, user{email: string(rune('a' + i%3))})
+			m.Lookup("email", "a")
+			if i%7 == 0 {
+				m.Delete(i % 10)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	indexed := 0
+	for _, ik := range []string{"a", "b", "c"} {
+		values, _ := m.Lookup("email", ik)
+		indexed += len(values)
+	}
+	if int64(indexed) != m.Len() {
+		t.Fatalf("expected every entry indexed once, got %d indexed for len %d", indexed, m.Len())
+	}
+}
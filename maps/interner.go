@@ -0,0 +1,101 @@
+package maps
+
+import (
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// Interner returns a canonical instance for equal values, so repeated
+// values share one copy in memory. Safe for concurrent use.
+//
+// When bounded, the least recently interned values are evicted first;
+// an evicted value simply gets a new canonical instance the next time.
+type Interner[T comparable] struct {
+	mu      sync.Mutex
+	maxSize int64
+	sizeOf  func(T) int
+	// values is kept in LRU order: the front is evicted first.
+	values *OrderedMap[T, T]
+	stats  InternerStats
+}
+
+// InternerStats reports the effect of an Interner.
+type InternerStats struct {
+	// Len is the number of canonical values currently held.
+	Len int64
+	// Hits counts Intern calls that returned an existing canonical value.
+	Hits uint64
+	// Misses counts Intern calls that added a new canonical value.
+	Misses uint64
+	// Evictions counts values dropped to stay within the size bound.
+	Evictions uint64
+	// BytesSaved is the total size, as reported by the size function,
+	// of the duplicates replaced by canonical values.
+	BytesSaved int64
+}
+
+// NewInterner returns an empty Interner holding at most maxSize values;
+// maxSize <= 0 means unbounded. sizeOf estimates the memory of a value for
+// InternerStats.BytesSaved; if nil, strings count their length in bytes and
+// other types their unsafe.Sizeof.
+func NewInterner[T comparable](maxSize int, sizeOf func(T) int) *Interner[T] {
+	if sizeOf == nil {
+		sizeOf = defaultSizeOf[T]
+	}
+	return &Interner[T]{
+		maxSize: int64(maxSize),
+		sizeOf:  sizeOf,
+		values:  NewOrderedMap[T, T](),
+	}
+}
+
+// Intern returns the canonical instance equal to value, making value
+// canonical if there is none yet. Strings are cloned before they become
+// canonical, so interning a substring never pins the larger buffer.
+func (i *Interner[T]) Intern(value T) T {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if canonical, ok := i.values.Load(value); ok {
+		i.values.MoveToBack(value)
+		i.stats.Hits++
+		i.stats.BytesSaved += int64(i.sizeOf(value))
+		return canonical
+	}
+
+	if s, ok := any(value).(string); ok {
+		value = any(strings.Clone(s)).(T)
+	}
+	i.values.Store(value, value)
+	i.stats.Misses++
+	if i.maxSize > 0 && i.values.Len() > i.maxSize {
+		oldest, _, _ := i.values.Front()
+		i.values.Delete(oldest)
+		i.stats.Evictions++
+	}
+	return value
+}
+
+// Len returns the number of canonical values currently held.
+func (i *Interner[T]) Len() int64 {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.values.Len()
+}
+
+// Stats returns a snapshot of the interner's counters.
+func (i *Interner[T]) Stats() InternerStats {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	res := i.stats
+	res.Len = i.values.Len()
+	return res
+}
+
+func defaultSizeOf[T comparable](value T) int {
+	if s, ok := any(value).(string); ok {
+		return len(s)
+	}
+	return int(unsafe.Sizeof(value))
+}
@@ -0,0 +1,99 @@
+package maps
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+func TestInterner_ReturnsCanonicalString(t *testing.T) {
+	in := NewInterner[string](0, nil)
+	a := in.Intern(fmt.Sprint("hello", 1))
+	b := in.Intern(fmt.Sprint("hello", 1))
+	if a != b {
+		t.Fatalf("expected equal values, got %q and %q", a, b)
+	}
+	if unsafe.StringData(a) != unsafe.StringData(b) {
+		t.Fatal("expected both results to share the canonical backing array")
+	}
+
+	stats := in.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 || stats.Len != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if stats.BytesSaved != int64(len("hello1")) {
+		t.Fatalf("expected %d bytes saved, got %d", len("hello1"), stats.BytesSaved)
+	}
+}
+
+func TestInterner_ClonesSubstrings(t *testing.T) {
+	in := NewInterner[string](0, nil)
+	buf := "key=value;other=stuff"
+	got := in.Intern(buf[:3])
+	if got != "key" {
+		t.Fatalf("expected \"key\", got %q", got)
+	}
+	if unsafe.StringData(got) == unsafe.StringData(buf) {
+		t.Fatal("expected canonical substring not to share the larger buffer")
+	}
+}
+
+func TestInterner_Bounded(t *testing.T) {
+	in := NewInterner[int](2, nil)
+	in.Intern(1)
+	in.Intern(2)
+	in.Intern(1) // 1 becomes most recently used
+	in.Intern(3) // evicts 2
+
+	if in.Len() != 2 {
+		t.Fatalf("expected len 2, got %d", in.Len())
+	}
+	stats := in.Stats()
+	if stats.Evictions != 1 {
+		t.Fatalf("expected 1 eviction, got %d", stats.Evictions)
+	}
+	in.Intern(1)
+	in.Intern(2)
+	stats = in.Stats()
+	if stats.Hits != 2 || stats.Misses != 4 {
+		t.Fatalf("expected 1 to survive and 2 to be evicted, got %+v", stats)
+	}
+	if stats.BytesSaved != 2*int64(unsafe.Sizeof(0)) {
+		t.Fatalf("expected default size of int per hit, got %d", stats.BytesSaved)
+	}
+}
+
+func TestInterner_CustomSizeOf(t *testing.T) {
+	type point struct{ X, Y int }
+	in := NewInterner(0, func(point) int { return 100 })
+	in.Intern(point{1, 2})
+	in.Intern(point{1, 2})
+	in.Intern(point{1, 2})
+	if got := in.Stats().BytesSaved; got != 200 {
+		t.Fatalf("expected 200 bytes saved, got %d", got)
+	}
+}
+
+func TestInterner_Concurrent(t *testing.T) {
+	in := NewInterner[string](0, nil)
+	wg := sync.WaitGroup{}
+	results := make([]string, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = in.Intern(fmt.Sprint("shared"))
+			in.Intern(fmt.Sprint("own", i))
+		}(i)
+	}
+	wg.Wait()
+	for _, r := range results {
+		if unsafe.StringData(r) != unsafe.StringData(results[0]) {
+			t.Fatal("expected all goroutines to receive the same canonical instance")
+		}
+	}
+	if in.Len() != 51 {
+		t.Fatalf("expected 51 canonical values, got %d", in.Len())
+	}
+}
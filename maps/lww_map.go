@@ -0,0 +1,165 @@
+package maps
+
+import (
+	"sync"
+	"time"
+)
+
+// LWWMap is a last-writer-wins element map, a state-based CRDT: replicas
+// accept writes independently and converge to the same contents once they
+// have merged each other's state, in any order and without a coordinator.
+// Safe for concurrent use.
+//
+// Every Store and Delete is stamped with the replica's HybridClock; on merge
+// the entry with the greater timestamp wins. Deletes are kept as tombstones
+// so they can win over older stores; see Compact.
+type LWWMap[K comparable, V any] struct {
+	mu      sync.RWMutex
+	clock   *HybridClock
+	entries map[K]LWWEntry[K, V]
+	live    int64
+}
+
+// LWWEntry is the replicated state of one key, as exchanged by Delta and
+// ApplyDelta. Deleted entries are tombstones.
+type LWWEntry[K comparable, V any] struct {
+	Key       K            `json:"key"`
+	Value     V            `json:"value"`
+	Timestamp HLCTimestamp `json:"ts"`
+	Deleted   bool         `json:"deleted,omitempty"`
+}
+
+// NewLWWMap returns an empty replica identified by nodeID, which must be
+// unique among replicas. now is passed to NewHybridClock.
+func NewLWWMap[K comparable, V any](nodeID string, now func() time.Time) *LWWMap[K, V] {
+	return &LWWMap[K, V]{
+		clock:   NewHybridClock(nodeID, now),
+		entries: make(map[K]LWWEntry[K, V]),
+	}
+}
+
+func (l *LWWMap[K, V]) Store(key K, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.set(LWWEntry[K, V]{Key: key, Value: value, Timestamp: l.clock.Now()})
+}
+
+func (l *LWWMap[K, V]) Load(key K) (V, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	e, ok := l.entries[key]
+	if !ok || e.Deleted {
+		var zero V
+		return zero, false
+	}
+	return e.Value, true
+}
+
+// Delete records a tombstone for key. The tombstone is written even if the
+// key is unknown locally, so it also wins over older stores on other replicas.
+func (l *LWWMap[K, V]) Delete(key K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.set(LWWEntry[K, V]{Key: key, Timestamp: l.clock.Now(), Deleted: true})
+}
+
+// Len returns the number of live (non-deleted) keys.
+func (l *LWWMap[K, V]) Len() int64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.live
+}
+
+// Range calls f for each live entry until f returns false. It iterates over
+// a copy taken under the lock, so f may modify the map.
+func (l *LWWMap[K, V]) Range(f func(key K, value V) bool) {
+	if f == nil {
+		return
+	}
+
+	l.mu.RLock()
+	keys := make([]K, 0, l.live)
+	values := make([]V, 0, l.live)
+	for k, e := range l.entries {
+		if !e.Deleted {
+			keys = append(keys, k)
+			values = append(values, e.Value)
+		}
+	}
+	l.mu.RUnlock()
+	rangeCollected(keys, values, f)
+}
+
+// Delta returns every entry, tombstones included, with a timestamp after
+// since. Pass the zero HLCTimestamp to export the full state.
+//
+// Merged entries keep their original timestamps, so with more than two
+// replicas a delta since the last exchange with a peer may miss entries
+// relayed from a third replica; exchange the full state periodically.
+func (l *LWWMap[K, V]) Delta(since HLCTimestamp) []LWWEntry[K, V] {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var res []LWWEntry[K, V]
+	for _, e := range l.entries {
+		if e.Timestamp.Compare(since) > 0 {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// ApplyDelta merges entries exported by another replica's Delta.
+func (l *LWWMap[K, V]) ApplyDelta(delta []LWWEntry[K, V]) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, e := range delta {
+		l.clock.Observe(e.Timestamp)
+		if cur, ok := l.entries[e.Key]; ok && cur.Timestamp.Compare(e.Timestamp) >= 0 {
+			continue
+		}
+		l.set(e)
+	}
+}
+
+// Merge merges the full state of other into l.
+func (l *LWWMap[K, V]) Merge(other *LWWMap[K, V]) {
+	l.ApplyDelta(other.Delta(HLCTimestamp{}))
+}
+
+// Compact drops tombstones written before the given timestamp and returns
+// how many were dropped. It is only safe once every replica has merged
+// those tombstones: a replica that has not may otherwise resurrect the
+// deleted keys on the next merge.
+func (l *LWWMap[K, V]) Compact(before HLCTimestamp) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	n := 0
+	for k, e := range l.entries {
+		if e.Deleted && e.Timestamp.Compare(before) < 0 {
+			delete(l.entries, k)
+			n++
+		}
+	}
+	return n
+}
+
+// set replaces key's entry and keeps the live count up to date.
+// Callers must hold l.mu.
+func (l *LWWMap[K, V]) set(e LWWEntry[K, V]) {
+	cur, existed := l.entries[e.Key]
+	wasLive := existed && !cur.Deleted
+	switch {
+	case !wasLive && !e.Deleted:
+		l.live++
+	case wasLive && e.Deleted:
+		l.live--
+	}
+	if e.Deleted {
+		var zero V
+		e.Value = zero
+	}
+	l.entries[e.Key] = e
+}
@@ -0,0 +1,203 @@
+package maps
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+func lwwContents(m *LWWMap[string, int]) map[string]int {
+	res := make(map[string]int)
+	m.Range(func(k string, v int) bool {
+		res[k] = v
+		return true
+	})
+	return res
+}
+
+func TestLWWMap_LocalOps(t *testing.T) {
+	m := NewLWWMap[string, int]("a", nil)
+	m.Store("x", 1)
+	m.Store("y", 2)
+	m.Store("x", 3)
+	if v, ok := m.Load("x"); !ok || v != 3 {
+		t.Fatalf("expected (3, true), got (%v, %v)", v, ok)
+	}
+	if m.Len() != 2 {
+		t.Fatalf("expected len 2, got %d", m.Len())
+	}
+	m.Delete("x")
+	m.Delete("unknown")
+	if _, ok := m.Load("x"); ok {
+		t.Fatal("expected key to be deleted")
+	}
+	if m.Len() != 1 {
+		t.Fatalf("expected len 1, got %d", m.Len())
+	}
+
+	times := 0
+	m.Store("z", 1)
+	m.Range(func(k string, v int) bool {
+		m.Delete(k) // modifying the map from f must not deadlock
+		times++
+		return false
+	})
+	if times != 1 {
+		t.Fatalf("expected Range to stop after first iteration, got %d iterations", times)
+	}
+	m.Range(nil)
+}
+
+func TestLWWMap_ConcurrentWritesConverge(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(100, 0)}
+	a := NewLWWMap[string, int]("a", clock.Now)
+	b := NewLWWMap[string, int]("b", clock.Now)
+	c := NewLWWMap[string, int]("c", clock.Now)
+
+	a.Store("flag", 1)
+	clock.Advance(time.Millisecond)
+	b.Store("flag", 2) // later write wins
+	c.Store("other", 3)
+	clock.Advance(time.Millisecond)
+	c.Delete("gone")
+	a.Store("gone", 4) // same wall time as c's delete: node ID "c" wins the tie
+
+	// merge in different orders
+	a.Merge(b)
+	a.Merge(c)
+	c.Merge(a)
+	b.Merge(c)
+
+	want := map[string]int{"flag": 2, "other": 3}
+	for name, m := range map[string]*LWWMap[string, int]{"a": a, "b": b, "c": c} {
+		got := lwwContents(m)
+		if len(got) != len(want) || got["flag"] != 2 || got["other"] != 3 {
+			t.Errorf("replica %s has %v, want %v", name, got, want)
+		}
+		if m.Len() != int64(len(want)) {
+			t.Errorf("replica %s has len %d, want %d", name, m.Len(), len(want))
+		}
+	}
+}
+
+func TestLWWMap_DeleteWinsOverOlderStore(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(100, 0)}
+	a := NewLWWMap[string, int]("a", clock.Now)
+	b := NewLWWMap[string, int]("b", clock.Now)
+
+	a.Store("k", 1)
+	b.Merge(a)
+	clock.Advance(time.Millisecond)
+	b.Delete("k")
+	a.Merge(b)
+	if _, ok := a.Load("k"); ok {
+		t.Fatal("expected newer tombstone to win")
+	}
+
+	// merging stale state back does not resurrect the key
+	stale := NewLWWMap[string, int]("c", clock.Now)
+	stale.ApplyDelta([]LWWEntry[string, int]{{Key: "k", Value: 1, Timestamp: HLCTimestamp{WallTime: 1, NodeID: "c"}}})
+	a.Merge(stale)
+	if _, ok := a.Load("k"); ok {
+		t.Fatal("expected stale store to lose against tombstone")
+	}
+}
+
+func TestLWWMap_Delta(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(100, 0)}
+	a := NewLWWMap[string, int]("a", clock.Now)
+	b := NewLWWMap[string, int]("b", clock.Now)
+
+	a.Store("x", 1)
+	first := a.Delta(HLCTimestamp{})
+	b.ApplyDelta(first)
+	synced := first[0].Timestamp
+
+	clock.Advance(time.Millisecond)
+	a.Store("y", 2)
+	a.Delete("x")
+	delta := a.Delta(synced)
+	if len(delta) != 2 {
+		t.Fatalf("expected 2 entries since last sync, got %d", len(delta))
+	}
+
+	// deltas survive a JSON round trip
+	data, err := json.Marshal(delta)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var decoded []LWWEntry[string, int]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	b.ApplyDelta(decoded)
+
+	if got := lwwContents(b); len(got) != 1 || got["y"] != 2 {
+		t.Fatalf("expected {y:2}, got %v", got)
+	}
+	// b's clock has observed a's timestamps
+	if ts := b.clock.Now(); ts.Compare(delta[0].Timestamp) <= 0 || ts.Compare(delta[1].Timestamp) <= 0 {
+		t.Fatalf("expected b's clock to advance past merged timestamps, got %v", ts)
+	}
+}
+
+func TestLWWMap_Compact(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(100, 0)}
+	m := NewLWWMap[string, int]("a", clock.Now)
+	m.Store("live", 1)
+	m.Store("old", 1)
+	m.Delete("old")
+	clock.Advance(time.Second)
+	horizon := m.clock.Now()
+	m.Store("new", 1)
+	m.Delete("new")
+
+	if n := m.Compact(horizon); n != 1 {
+		t.Fatalf("expected 1 tombstone compacted, got %d", n)
+	}
+	if _, ok := m.entries["old"]; ok {
+		t.Fatal("expected old tombstone to be dropped")
+	}
+	if e, ok := m.entries["new"]; !ok || !e.Deleted {
+		t.Fatal("expected recent tombstone to be kept")
+	}
+	if m.Len() != 1 {
+		t.Fatalf("expected len 1, got %d", m.Len())
+	}
+}
+
+func TestLWWMap_ConcurrentAccess(t *testing.T) {
+	a := NewLWWMap[int, int]("a", nil)
+	b := NewLWWMap[int, int]("b", nil)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(k int) {
+			defer wg.Done()
+			a.Store(k, k)
+			a.Merge(b)
+		}(i)
+		go func(k int) {
+			defer wg.Done()
+			b.Store(k, -k)
+			b.Delete(k + 100)
+			b.Merge(a)
+		}(i)
+	}
+	wg.Wait()
+	a.Merge(b)
+	b.Merge(a)
+
+	ca, cb := make(map[int]int), make(map[int]int)
+	a.Range(func(k, v int) bool { ca[k] = v; return true })
+	b.Range(func(k, v int) bool { cb[k] = v; return true })
+	if len(ca) != 20 || len(ca) != len(cb) {
+		t.Fatalf("expected 20 converged keys, got %d and %d", len(ca), len(cb))
+	}
+	for k, v := range ca {
+		if cb[k] != v {
+			t.Fatalf("replicas diverge on key %d: %d vs %d", k, v, cb[k])
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package mapstest
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/NLipatov/goutils/maps"
+)
+
+// OpKind is the kind of a recorded operation.
+type OpKind int
+
+const (
+	OpStore OpKind = iota
+	OpLoad
+	OpDelete
+)
+
+func (k OpKind) String() string {
+	switch k {
+	case OpStore:
+		return "Store"
+	case OpLoad:
+		return "Load"
+	case OpDelete:
+		return "Delete"
+	default:
+		return "OpKind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// Operation is one call in a concurrent history. Start and End are logical
+// timestamps taken just before the call and just after it returned, so
+// a.End < b.Start means a completed before b was invoked.
+type Operation struct {
+	Client int
+	Kind   OpKind
+	Key    string
+	// Value is the argument of a Store and the result of a Load.
+	Value int
+	// Ok is the second result of a Load.
+	Ok    bool
+	Start int64
+	End   int64
+}
+
+func (op Operation) String() string {
+	switch op.Kind {
+	case OpStore:
+		return fmt.Sprintf("client %d: Store(%q, %d) [%d, %d]", op.Client, op.Key, op.Value, op.Start, op.End)
+	case OpLoad:
+		return fmt.Sprintf("client %d: Load(%q) = (%d, %v) [%d, %d]", op.Client, op.Key, op.Value, op.Ok, op.Start, op.End)
+	default:
+		return fmt.Sprintf("client %d: %s(%q) [%d, %d]", op.Client, op.Kind, op.Key, op.Start, op.End)
+	}
+}
+
+// HistoryOptions configures RecordHistory. Zero fields take defaults.
+type HistoryOptions struct {
+	// Clients is the number of concurrent goroutines; defaults to 4.
+	Clients int
+	// OpsPerClient defaults to 200.
+	OpsPerClient int
+	// Keys is the size of the key space; defaults to 8. Fewer keys mean
+	// more contention per key and a more expensive check.
+	Keys int
+	// Seed makes the chosen operations reproducible; the interleaving
+	// is still up to the scheduler.
+	Seed uint64
+}
+
+// RecordHistory runs random Store, Load and Delete calls against m from
+// concurrent clients and returns every call with its timing, ordered by Start.
+func RecordHistory(m maps.ConcurrentMap[string, int], opts HistoryOptions) []Operation {
+	if opts.Clients <= 0 {
+		opts.Clients = 4
+	}
+	if opts.OpsPerClient <= 0 {
+		opts.OpsPerClient = 200
+	}
+	if opts.Keys <= 0 {
+		opts.Keys = 8
+	}
+
+	var clock atomic.Int64
+	histories := make([][]Operation, opts.Clients)
+	wg := sync.WaitGroup{}
+	for c := range opts.Clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rnd := rand.New(rand.NewPCG(opts.Seed, uint64(c)))
+			ops := make([]Operation, 0, opts.OpsPerClient)
+			for i := range opts.OpsPerClient {
+				op := Operation{
+					Client: c,
+					Key:    "k" + strconv.Itoa(rnd.IntN(opts.Keys)),
+				}
+				switch n := rnd.IntN(10); {
+				case n < 4:
+					op.Kind = OpStore
+					// unique values let the checker tell writes apart
+					op.Value = c*opts.OpsPerClient + i
+					op.Start = clock.Add(1)
+					m.Store(op.Key, op.Value)
+				case n < 8:
+					op.Kind = OpLoad
+					op.Start = clock.Add(1)
+					op.Value, op.Ok = m.Load(op.Key)
+				default:
+					op.Kind = OpDelete
+					op.Start = clock.Add(1)
+					m.Delete(op.Key)
+				}
+				op.End = clock.Add(1)
+				ops = append(ops, op)
+			}
+			histories[c] = ops
+		}()
+	}
+	wg.Wait()
+
+	var res []Operation
+	for _, ops := range histories {
+		res = append(res, ops...)
+	}
+	sortByStart(res)
+	return res
+}
@@ -0,0 +1,163 @@
+package mapstest
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrNotLinearizable is returned by CheckLinearizable for a history that
+// no sequential execution of a map can explain.
+var ErrNotLinearizable = errors.New("history is not linearizable")
+
+// CheckLinearizable reports whether history is linearizable with respect
+// to a sequential map: whether every operation can be assigned a point
+// between its Start and End such that, in that order, each Load returns
+// the latest stored value of its key.
+//
+// Operations on different keys commute, so each key is checked on its own
+// with a backtracking search over the orders its timing allows. The search
+// is exponential in the worst case; keep per-key concurrency modest.
+func CheckLinearizable(history []Operation) error {
+	byKey := make(map[string][]Operation)
+	for _, op := range history {
+		byKey[op.Key] = append(byKey[op.Key], op)
+	}
+
+	keys := make([]string, 0, len(byKey))
+	for k := range byKey {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		ops := byKey[k]
+		sortByStart(ops)
+		if !newKeyChecker(ops).check() {
+			return fmt.Errorf("%w: key %q:\n%s", ErrNotLinearizable, k, formatOps(ops))
+		}
+	}
+	return nil
+}
+
+// registerState is the sequential model of a single key.
+type registerState struct {
+	value   int
+	present bool
+}
+
+// apply returns the state after op, or false if op's result contradicts s.
+func (s registerState) apply(op Operation) (registerState, bool) {
+	switch op.Kind {
+	case OpStore:
+		return registerState{value: op.Value, present: true}, true
+	case OpDelete:
+		return registerState{}, true
+	default:
+		if op.Ok != s.present || (op.Ok && op.Value != s.value) {
+			return s, false
+		}
+		return s, true
+	}
+}
+
+type keyChecker struct {
+	ops  []Operation
+	done []uint64 // bitset of linearized operations
+	// visited holds the (done, state) pairs already explored without success.
+	visited map[string]struct{}
+}
+
+func newKeyChecker(ops []Operation) *keyChecker {
+	return &keyChecker{
+		ops:     ops,
+		done:    make([]uint64, (len(ops)+63)/64),
+		visited: make(map[string]struct{}),
+	}
+}
+
+func (c *keyChecker) check() bool {
+	return c.search(registerState{}, 0)
+}
+
+func (c *keyChecker) search(state registerState, linearized int) bool {
+	if linearized == len(c.ops) {
+		return true
+	}
+	memo := c.memoKey(state)
+	if _, ok := c.visited[memo]; ok {
+		return false
+	}
+
+	// an operation can go next only if no pending operation completed
+	// before it was invoked; ops are ordered by Start, so candidates are a prefix
+	minEnd := int64(-1)
+	for i, op := range c.ops {
+		if c.isDone(i) {
+			continue
+		}
+		if minEnd >= 0 && op.Start > minEnd {
+			break
+		}
+		if minEnd < 0 || op.End < minEnd {
+			minEnd = op.End
+		}
+	}
+	for i, op := range c.ops {
+		if c.isDone(i) {
+			continue
+		}
+		if op.Start > minEnd {
+			break
+		}
+		next, ok := state.apply(op)
+		if !ok {
+			continue
+		}
+		c.setDone(i, true)
+		found := c.search(next, linearized+1)
+		c.setDone(i, false)
+		if found {
+			return true
+		}
+	}
+
+	c.visited[memo] = struct{}{}
+	return false
+}
+
+func (c *keyChecker) isDone(i int) bool {
+	return c.done[i/64]&(1<<(i%64)) != 0
+}
+
+func (c *keyChecker) setDone(i int, done bool) {
+	if done {
+		c.done[i/64] |= 1 << (i % 64)
+	} else {
+		c.done[i/64] &^= 1 << (i % 64)
+	}
+}
+
+func (c *keyChecker) memoKey(state registerState) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v:%d:", state.present, state.value)
+	for _, w := range c.done {
+		fmt.Fprintf(&b, "%x,", w)
+	}
+	return b.String()
+}
+
+func sortByStart(ops []Operation) {
+	slices.SortFunc(ops, func(a, b Operation) int {
+		return cmp.Compare(a.Start, b.Start)
+	})
+}
+
+func formatOps(ops []Operation) string {
+	lines := make([]string, len(ops))
+	for i, op := range ops {
+		lines[i] = "\t" + op.String()
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,122 @@
+// Package mapstest checks map implementations against the semantics of
+// maps.TypedSyncMap: a behaviour suite, a randomized concurrent history
+// recorder and a linearizability checker for maps.ConcurrentMap.
+//
+// Implementations generic over their key and value types are exercised
+// with string keys and int values.
+package mapstest
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/NLipatov/goutils/maps"
+)
+
+// RunConformance runs the behaviour suite as subtests of t. newMap must
+// return a new empty map on every call.
+func RunConformance(t *testing.T, newMap func() maps.ConcurrentMap[string, int]) {
+	t.Helper()
+	t.Run("StoreLoadDelete", func(t *testing.T) { testStoreLoadDelete(t, newMap()) })
+	t.Run("Range", func(t *testing.T) { testRange(t, newMap()) })
+	t.Run("Concurrent", func(t *testing.T) { testConcurrent(t, newMap()) })
+	t.Run("Linearizable", func(t *testing.T) {
+		history := RecordHistory(newMap(), HistoryOptions{})
+		if err := CheckLinearizable(history); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func testStoreLoadDelete(t *testing.T, m maps.ConcurrentMap[string, int]) {
+	if _, ok := m.Load("a"); ok {
+		t.Fatal("expected empty map")
+	}
+	m.Store("a", 1)
+	m.Store("b", 2)
+	m.Store("a", 3)
+	if v, ok := m.Load("a"); !ok || v != 3 {
+		t.Fatalf("expected (3, true), got (%v, %v)", v, ok)
+	}
+	if m.Len() != 2 {
+		t.Fatalf("expected len 2, got %d", m.Len())
+	}
+
+	m.Delete("a")
+	m.Delete("missing")
+	if _, ok := m.Load("a"); ok {
+		t.Fatal("expected key to be deleted")
+	}
+	if m.Len() != 1 {
+		t.Fatalf("expected len 1, got %d", m.Len())
+	}
+}
+
+func testRange(t *testing.T, m maps.ConcurrentMap[string, int]) {
+	want := map[string]int{"a": 1, "b": 2, "c": 3}
+	for k, v := range want {
+		m.Store(k, v)
+	}
+
+	seen := make(map[string]int)
+	m.Range(func(k string, v int) bool {
+		if _, dup := seen[k]; dup {
+			t.Fatalf("key %q visited twice", k)
+		}
+		seen[k] = v
+		return true
+	})
+	if len(seen) != len(want) {
+		t.Fatalf("expected %v, got %v", want, seen)
+	}
+	for k, v := range want {
+		if seen[k] != v {
+			t.Fatalf("expected %v, got %v", want, seen)
+		}
+	}
+
+	times := 0
+	m.Range(func(k string, v int) bool {
+		times++
+		return false
+	})
+	if times != 1 {
+		t.Fatalf("expected Range to stop after first iteration, got %d iterations", times)
+	}
+}
+
+func testConcurrent(t *testing.T, m maps.ConcurrentMap[string, int]) {
+	wg := sync.WaitGroup{}
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				k := strconv.Itoa(g*100 + i)
+				m.Store(k, i)
+				if v, ok := m.Load(k); !ok || v != i {
+					t.Errorf("expected (%d, true) for %q, got (%d, %v)", i, k, v, ok)
+					return
+				}
+				if i%2 == 1 {
+					m.Delete(k)
+				}
+			}
+			m.Len()
+		}(g)
+	}
+	wg.Wait()
+
+	if m.Len() != 400 {
+		t.Fatalf("expected len 400, got %d", m.Len())
+	}
+	n := 0
+	m.Range(func(k string, v int) bool {
+		n++
+		return true
+	})
+	if n != 400 {
+		t.Fatalf("expected Range to visit 400 entries, got %d", n)
+	}
+}
@@ -0,0 +1,139 @@
+package mapstest
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/NLipatov/goutils/maps"
+)
+
+func TestCheckLinearizable_Sequential(t *testing.T) {
+	history := []Operation{
+		{Kind: OpLoad, Key: "a", Start: 1, End: 2},
+		{Kind: OpStore, Key: "a", Value: 1, Start: 3, End: 4},
+		{Kind: OpLoad, Key: "a", Value: 1, Ok: true, Start: 5, End: 6},
+		{Kind: OpDelete, Key: "a", Start: 7, End: 8},
+		{Kind: OpLoad, Key: "a", Start: 9, End: 10},
+	}
+	if err := CheckLinearizable(history); err != nil {
+		t.Fatalf("expected linearizable history, got %v", err)
+	}
+}
+
+func TestCheckLinearizable_StaleRead(t *testing.T) {
+	history := []Operation{
+		{Kind: OpStore, Key: "a", Value: 1, Start: 1, End: 2},
+		{Kind: OpStore, Key: "a", Value: 2, Start: 3, End: 4},
+		{Kind: OpLoad, Key: "a", Value: 1, Ok: true, Start: 5, End: 6},
+	}
+	if err := CheckLinearizable(history); !errors.Is(err, ErrNotLinearizable) {
+		t.Fatalf("expected ErrNotLinearizable, got %v", err)
+	}
+}
+
+func TestCheckLinearizable_OverlappingOperations(t *testing.T) {
+	// the Load overlaps both Stores, so it may observe either of them
+	// but, once a later Load saw 2, not 1 afterwards
+	history := []Operation{
+		{Client: 0, Kind: OpStore, Key: "a", Value: 1, Start: 1, End: 6},
+		{Client: 1, Kind: OpStore, Key: "a", Value: 2, Start: 2, End: 5},
+		{Client: 2, Kind: OpLoad, Key: "a", Value: 1, Ok: true, Start: 3, End: 4},
+		{Client: 2, Kind: OpLoad, Key: "a", Value: 2, Ok: true, Start: 7, End: 8},
+	}
+	if err := CheckLinearizable(history); err != nil {
+		t.Fatalf("expected linearizable history, got %v", err)
+	}
+
+	history = append(history, Operation{Client: 2, Kind: OpLoad, Key: "a", Value: 1, Ok: true, Start: 9, End: 10})
+	if err := CheckLinearizable(history); !errors.Is(err, ErrNotLinearizable) {
+		t.Fatalf("expected ErrNotLinearizable, got %v", err)
+	}
+}
+
+func TestCheckLinearizable_KeysAreIndependent(t *testing.T) {
+	history := []Operation{
+		{Kind: OpStore, Key: "a", Value: 1, Start: 1, End: 2},
+		{Kind: OpLoad, Key: "b", Start: 3, End: 4},
+		{Kind: OpLoad, Key: "a", Value: 1, Ok: true, Start: 5, End: 6},
+	}
+	if err := CheckLinearizable(history); err != nil {
+		t.Fatalf("expected linearizable history, got %v", err)
+	}
+}
+
+func TestRecordHistory(t *testing.T) {
+	m := maps.NewTypedSyncMap[string, int]()
+	history := RecordHistory(m, HistoryOptions{Clients: 3, OpsPerClient: 50, Keys: 4, Seed: 1})
+	if len(history) != 150 {
+		t.Fatalf("expected 150 operations, got %d", len(history))
+	}
+	for i, op := range history {
+		if op.Start >= op.End {
+			t.Fatalf("expected Start < End, got %v", op)
+		}
+		if i > 0 && history[i-1].Start > op.Start {
+			t.Fatal("expected history ordered by Start")
+		}
+	}
+	if err := CheckLinearizable(history); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// laggingMap serves Loads from the value stored before the latest one.
+type laggingMap struct {
+	mu      sync.Mutex
+	current map[string]int
+	prev    map[string]int
+}
+
+func (l *laggingMap) Store(key string, value int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if v, ok := l.current[key]; ok {
+		l.prev[key] = v
+	}
+	l.current[key] = value
+}
+
+func (l *laggingMap) Load(key string) (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if v, ok := l.prev[key]; ok {
+		return v, true
+	}
+	v, ok := l.current[key]
+	return v, ok
+}
+
+func (l *laggingMap) Delete(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.current, key)
+	delete(l.prev, key)
+}
+
+func (l *laggingMap) Len() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int64(len(l.current))
+}
+
+func (l *laggingMap) Range(f func(key string, value int) bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for k, v := range l.current {
+		if !f(k, v) {
+			return
+		}
+	}
+}
+
+func TestCheckLinearizable_DetectsBrokenMap(t *testing.T) {
+	m := &laggingMap{current: make(map[string]int), prev: make(map[string]int)}
+	history := RecordHistory(m, HistoryOptions{Seed: 1})
+	if err := CheckLinearizable(history); !errors.Is(err, ErrNotLinearizable) {
+		t.Fatalf("expected ErrNotLinearizable, got %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package maps
+
+const (
+	defaultMerkleDepth = 10
+	maxMerkleDepth     = 24
+)
+
+// MerkleTree is a digest of a map's contents used to find the parts in
+// which two maps differ without exchanging the maps themselves.
+//
+// Keys are partitioned into 2^depth buckets by the top bits of their key
+// hash. A leaf hashes the entries of one bucket independently of their
+// order, and every inner node hashes its two children. The tree is a
+// static snapshot: rebuild it after the map changes.
+type MerkleTree struct {
+	depth int
+	// levels[0] holds the root, levels[depth] the leaves.
+	levels [][]uint64
+}
+
+// HashRange is an inclusive range of key hashes covered by a Merkle leaf.
+type HashRange struct {
+	Start, End uint64
+}
+
+// Contains reports whether hash lies within r.
+func (r HashRange) Contains(hash uint64) bool {
+	return hash >= r.Start && hash <= r.End
+}
+
+// NewMerkleTree builds a tree of the given depth over the entries of src.
+// Depths outside [1, 24] fall back to 10.
+//
+// keyHash places keys into buckets by its top bits, so it should spread
+// similar keys well (e.g. xxhash over an encoded key). entryHash digests an
+// entry. Both must be non-nil and deterministic across the compared maps,
+// and trees are only comparable if built with the same functions and depth.
+func NewMerkleTree[K comparable, V any](
+	src Ranger[K, V],
+	depth int,
+	keyHash func(K) uint64,
+	entryHash func(K, V) uint64,
+) *MerkleTree {
+	if depth < 1 || depth > maxMerkleDepth {
+		depth = defaultMerkleDepth
+	}
+
+	t := &MerkleTree{
+		depth:  depth,
+		levels: make([][]uint64, depth+1),
+	}
+	for lvl := range t.levels {
+		t.levels[lvl] = make([]uint64, 1<<lvl)
+	}
+
+	leaves := t.levels[depth]
+	src.Range(func(k K, v V) bool {
+		// summing mixed entry hashes makes leaves independent of Range order
+		leaves[keyHash(k)>>(64-depth)] += mix64(entryHash(k, v))
+		return true
+	})
+	for lvl := depth - 1; lvl >= 0; lvl-- {
+		children := t.levels[lvl+1]
+		for i := range t.levels[lvl] {
+			t.levels[lvl][i] = combineHashes(children[2*i], children[2*i+1])
+		}
+	}
+	return t
+}
+
+// Depth returns the number of levels below the root.
+func (t *MerkleTree) Depth() int {
+	return t.depth
+}
+
+// Root returns the hash of the whole tree.
+func (t *MerkleTree) Root() uint64 {
+	return t.levels[0][0]
+}
+
+// Hashes returns the hashes of the nodes at level with the given indices.
+func (t *MerkleTree) Hashes(level int, indices []int) ([]uint64, error) {
+	if level < 0 || level > t.depth {
+		return nil, ErrMerkleLevel
+	}
+	res := make([]uint64, len(indices))
+	for i, idx := range indices {
+		if idx < 0 || idx >= len(t.levels[level]) {
+			return nil, ErrMerkleLevel
+		}
+		res[i] = t.levels[level][idx]
+	}
+	return res, nil
+}
+
+// MerklePeer gives access to a remote MerkleTree. Every Hashes call is
+// one round trip.
+type MerklePeer interface {
+	Depth() (int, error)
+	Hashes(level int, indices []int) ([]uint64, error)
+}
+
+// Diff compares local with the tree behind peer and returns the hash ranges
+// whose entries differ, with adjacent ranges merged. It descends only into
+// differing subtrees, one level per round, so it needs depth+1 rounds of
+// Hashes plus one Depth call. Entries in the returned ranges can then be
+// exchanged, see EntriesInRanges.
+func Diff(local *MerkleTree, peer MerklePeer) ([]HashRange, error) {
+	depth, err := peer.Depth()
+	if err != nil {
+		return nil, err
+	}
+	if depth != local.depth {
+		return nil, ErrMerkleDepthMismatch
+	}
+
+	candidates := []int{0}
+	for lvl := 0; lvl <= local.depth && len(candidates) > 0; lvl++ {
+		if lvl > 0 {
+			children := make([]int, 0, 2*len(candidates))
+			for _, c := range candidates {
+				children = append(children, 2*c, 2*c+1)
+			}
+			candidates = children
+		}
+
+		remote, err := peer.Hashes(lvl, candidates)
+		if err != nil {
+			return nil, err
+		}
+		if len(remote) != len(candidates) {
+			return nil, ErrMerkleHashCount
+		}
+		differing := candidates[:0]
+		for i, idx := range candidates {
+			if local.levels[lvl][idx] != remote[i] {
+				differing = append(differing, idx)
+			}
+		}
+		candidates = differing
+	}
+
+	var res []HashRange
+	width := uint64(1) << (64 - local.depth)
+	for _, leaf := range candidates {
+		start := uint64(leaf) << (64 - local.depth)
+		if n := len(res); n > 0 && res[n-1].End+1 == start {
+			res[n-1].End = start + width - 1
+			continue
+		}
+		res = append(res, HashRange{Start: start, End: start + width - 1})
+	}
+	return res, nil
+}
+
+// EntriesInRanges returns the entries of src whose key hash lies in one of ranges.
+func EntriesInRanges[K comparable, V any](src Ranger[K, V], keyHash func(K) uint64, ranges []HashRange) map[K]V {
+	res := make(map[K]V)
+	src.Range(func(k K, v V) bool {
+		h := keyHash(k)
+		for _, r := range ranges {
+			if r.Contains(h) {
+				res[k] = v
+				break
+			}
+		}
+		return true
+	})
+	return res
+}
+
+// InMemoryMerklePeer is a MerklePeer backed by a local tree, for tests and
+// in-process reconciliation. It counts the rounds it serves.
+type InMemoryMerklePeer struct {
+	tree   *MerkleTree
+	rounds int
+}
+
+// NewInMemoryMerklePeer returns a peer serving tree.
+func NewInMemoryMerklePeer(tree *MerkleTree) *InMemoryMerklePeer {
+	return &InMemoryMerklePeer{tree: tree}
+}
+
+func (p *InMemoryMerklePeer) Depth() (int, error) {
+	return p.tree.Depth(), nil
+}
+
+func (p *InMemoryMerklePeer) Hashes(level int, indices []int) ([]uint64, error) {
+	p.rounds++
+	return p.tree.Hashes(level, indices)
+}
+
+// Rounds returns the number of Hashes calls served so far.
+func (p *InMemoryMerklePeer) Rounds() int {
+	return p.rounds
+}
+
+// mix64 is the splitmix64 finaliser.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func combineHashes(left, right uint64) uint64 {
+	return mix64(left ^ mix64(right+0x9e3779b97f4a7c15))
+}
@@ -0,0 +1,162 @@
+package maps
+
+import (
+	"errors"
+	"hash/fnv"
+	"strconv"
+	"testing"
+)
+
+func fnvKey(k string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(k))
+	// FNV's high bits vary little between similar keys; buckets use the top bits
+	return mix64(h.Sum64())
+}
+
+func fnvEntry(k string, v int) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(k))
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.Itoa(v)))
+	return h.Sum64()
+}
+
+func merkleFixture(n int) *TypedSyncMap[string, int] {
+	m := NewTypedSyncMap[string, int]()
+	for i := 0; i < n; i++ {
+		m.Store("key-"+strconv.Itoa(i), i)
+	}
+	return m
+}
+
+func TestMerkleTree_EqualMaps(t *testing.T) {
+	a := NewMerkleTree[string, int](merkleFixture(1000), 8, fnvKey, fnvEntry)
+	// a different map type with the same contents yields the same digest
+	b := NewMerkleTree[string, int](NewFromMap(merkleFixture(1000).ToMap()), 8, fnvKey, fnvEntry)
+	if a.Root() != b.Root() {
+		t.Fatal("expected equal roots for equal contents")
+	}
+
+	peer := NewInMemoryMerklePeer(b)
+	ranges, err := Diff(a, peer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ranges) != 0 {
+		t.Fatalf("expected no differences, got %v", ranges)
+	}
+	if peer.Rounds() != 1 {
+		t.Fatalf("expected a single round for equal trees, got %d", peer.Rounds())
+	}
+}
+
+func TestMerkleTree_DiffFindsChangedKeys(t *testing.T) {
+	local := merkleFixture(1000)
+	remote := merkleFixture(1000)
+	remote.Store("key-42", -1)   // changed value
+	remote.Delete("key-7")       // only on local
+	remote.Store("extra", 12345) // only on remote
+
+	const depth = 12
+	lt := NewMerkleTree[string, int](local, depth, fnvKey, fnvEntry)
+	rt := NewMerkleTree[string, int](remote, depth, fnvKey, fnvEntry)
+	peer := NewInMemoryMerklePeer(rt)
+
+	ranges, err := Diff(lt, peer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if peer.Rounds() != depth+1 {
+		t.Fatalf("expected %d rounds, got %d", depth+1, peer.Rounds())
+	}
+	if len(ranges) == 0 || len(ranges) > 3 {
+		t.Fatalf("expected 1 to 3 differing ranges, got %v", ranges)
+	}
+
+	localDiff := EntriesInRanges[string, int](local, fnvKey, ranges)
+	remoteDiff := EntriesInRanges[string, int](remote, fnvKey, ranges)
+	if _, ok := localDiff["key-7"]; !ok {
+		t.Error("expected key-7 among local entries to exchange")
+	}
+	if v, ok := remoteDiff["key-42"]; !ok || v != -1 {
+		t.Errorf("expected key-42 among remote entries to exchange, got (%v, %v)", v, ok)
+	}
+	if _, ok := remoteDiff["extra"]; !ok {
+		t.Error("expected extra among remote entries to exchange")
+	}
+	if len(localDiff) > 10 {
+		t.Errorf("expected only a few entries to exchange, got %d", len(localDiff))
+	}
+
+	// applying the remote entries of the differing ranges reconciles the maps
+	for k := range localDiff {
+		local.Delete(k)
+	}
+	for k, v := range remoteDiff {
+		local.Store(k, v)
+	}
+	if NewMerkleTree[string, int](local, depth, fnvKey, fnvEntry).Root() != rt.Root() {
+		t.Fatal("expected maps to be reconciled")
+	}
+}
+
+func TestMerkleTree_AdjacentRangesAreMerged(t *testing.T) {
+	a := NewTypedSyncMap[uint64, int]()
+	b := NewTypedSyncMap[uint64, int]()
+	identity := func(k uint64) uint64 { return k }
+	entry := func(k uint64, v int) uint64 { return k ^ uint64(v) }
+	// keys in leaves 0 and 1 of a depth-2 tree
+	b.Store(1, 2)
+	b.Store(1<<62, 2)
+
+	ranges, err := Diff(
+		NewMerkleTree[uint64, int](a, 2, identity, entry),
+		NewInMemoryMerklePeer(NewMerkleTree[uint64, int](b, 2, identity, entry)),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := HashRange{Start: 0, End: 1<<63 - 1}
+	if len(ranges) != 1 || ranges[0] != want {
+		t.Fatalf("expected single merged range %v, got %v", want, ranges)
+	}
+	if !want.Contains(1<<62) || want.Contains(1<<63) {
+		t.Fatal("unexpected Contains result")
+	}
+}
+
+func TestMerkleTree_Errors(t *testing.T) {
+	m := merkleFixture(10)
+	a := NewMerkleTree[string, int](m, 4, fnvKey, fnvEntry)
+	b := NewMerkleTree[string, int](m, 5, fnvKey, fnvEntry)
+	if _, err := Diff(a, NewInMemoryMerklePeer(b)); !errors.Is(err, ErrMerkleDepthMismatch) {
+		t.Fatalf("expected ErrMerkleDepthMismatch, got %v", err)
+	}
+	if _, err := a.Hashes(5, []int{0}); !errors.Is(err, ErrMerkleLevel) {
+		t.Fatalf("expected ErrMerkleLevel for level, got %v", err)
+	}
+	if _, err := a.Hashes(1, []int{2}); !errors.Is(err, ErrMerkleLevel) {
+		t.Fatalf("expected ErrMerkleLevel for index, got %v", err)
+	}
+	if d := NewMerkleTree[string, int](m, 0, fnvKey, fnvEntry).Depth(); d != defaultMerkleDepth {
+		t.Fatalf("expected default depth %d, got %d", defaultMerkleDepth, d)
+	}
+	if _, err := Diff(a, shortPeer{a}); !errors.Is(err, ErrMerkleHashCount) {
+		t.Fatalf("expected ErrMerkleHashCount, got %v", err)
+	}
+}
+
+// shortPeer drops one hash from every answer.
+type shortPeer struct {
+	tree *MerkleTree
+}
+
+func (p shortPeer) Depth() (int, error) {
+	return p.tree.Depth(), nil
+}
+
+func (p shortPeer) Hashes(level int, indices []int) ([]uint64, error) {
+	res, err := p.tree.Hashes(level, indices)
+	return res[:len(res)-1], err
+}
@@ -0,0 +1,130 @@
+package maps
+
+import (
+	"slices"
+	"sync"
+)
+
+// MultiMapMode selects how a MultiMap treats repeated values under one key.
+type MultiMapMode int
+
+const (
+	// MultiMapList keeps every added value, including duplicates, in insertion order.
+	MultiMapList MultiMapMode = iota
+	// MultiMapSet keeps each distinct value once per key, in insertion order.
+	MultiMapSet
+)
+
+// MultiMap is a concurrent map from a key to a collection of values.
+// Safe for concurrent use.
+//
+// Values of one key are kept in a slice: Add in MultiMapSet mode and
+// Remove are linear in the number of values under that key.
+type MultiMap[K comparable, V comparable] struct {
+	mu    sync.RWMutex
+	mode  MultiMapMode
+	items map[K][]V
+}
+
+// NewMultiMap returns a new empty MultiMap using mode.
+func NewMultiMap[K comparable, V comparable](mode MultiMapMode) *MultiMap[K, V] {
+	return &MultiMap[K, V]{
+		mode:  mode,
+		items: make(map[K][]V),
+	}
+}
+
+// Add appends value to key's collection. In MultiMapSet mode it returns
+// false and leaves the map unchanged if value is already present.
+func (m *MultiMap[K, V]) Add(key K, value V) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	values := m.items[key]
+	if m.mode == MultiMapSet && slices.Contains(values, value) {
+		return false
+	}
+	m.items[key] = append(values, value)
+	return true
+}
+
+// Remove deletes the first occurrence of value from key's collection and
+// reports whether it was present. A key without values is removed.
+func (m *MultiMap[K, V]) Remove(key K, value V) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	values := m.items[key]
+	i := slices.Index(values, value)
+	if i < 0 {
+		return false
+	}
+	values = slices.Delete(values, i, i+1)
+	if len(values) == 0 {
+		delete(m.items, key)
+	} else {
+		m.items[key] = values
+	}
+	return true
+}
+
+// RemoveAll deletes key with all its values and returns how many values were removed.
+func (m *MultiMap[K, V]) RemoveAll(key K) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := len(m.items[key])
+	delete(m.items, key)
+	return n
+}
+
+// Get returns a copy of key's values, or nil if key is absent.
+func (m *MultiMap[K, V]) Get(key K) []V {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.items[key])
+}
+
+// Contains reports whether value is in key's collection.
+func (m *MultiMap[K, V]) Contains(key K, value V) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Contains(m.items[key], value)
+}
+
+// Count returns the number of values under key.
+func (m *MultiMap[K, V]) Count(key K) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.items[key])
+}
+
+// Len returns the number of keys.
+func (m *MultiMap[K, V]) Len() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return int64(len(m.items))
+}
+
+// Range calls f with a copy of each key's values until f returns false.
+// The copies are taken under the lock before f is called, so f may modify the map.
+func (m *MultiMap[K, V]) Range(f func(key K, values []V) bool) {
+	if f == nil {
+		return
+	}
+
+	m.mu.RLock()
+	keys := make([]K, 0, len(m.items))
+	values := make([][]V, 0, len(m.items))
+	for k, v := range m.items {
+		keys = append(keys, k)
+		values = append(values, slices.Clone(v))
+	}
+	m.mu.RUnlock()
+
+	for i := range keys {
+		if !f(keys[i], values[i]) {
+			return
+		}
+	}
+}
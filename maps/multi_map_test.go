@@ -0,0 +1,133 @@
+package maps
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestMultiMap_ListMode(t *testing.T) {
+	m := NewMultiMap[string, int](MultiMapList)
+	m.Add("a", 1)
+	m.Add("a", 2)
+	if !m.Add("a", 1) {
+		t.Fatal("expected duplicate Add to succeed in list mode")
+	}
+	if got := m.Get("a"); !slices.Equal(got, []int{1, 2, 1}) {
+		t.Fatalf("expected [1 2 1], got %v", got)
+	}
+	if m.Count("a") != 3 {
+		t.Fatalf("expected count 3, got %d", m.Count("a"))
+	}
+
+	if !m.Remove("a", 1) {
+		t.Fatal("expected Remove of present value to succeed")
+	}
+	if got := m.Get("a"); !slices.Equal(got, []int{2, 1}) {
+		t.Fatalf("expected first occurrence removed, got %v", got)
+	}
+	if m.Remove("a", 42) || m.Remove("missing", 1) {
+		t.Fatal("expected Remove of absent value to fail")
+	}
+}
+
+func TestMultiMap_SetMode(t *testing.T) {
+	m := NewMultiMap[string, int](MultiMapSet)
+	if !m.Add("a", 1) || !m.Add("a", 2) {
+		t.Fatal("expected Add of new values to succeed")
+	}
+	if m.Add("a", 1) {
+		t.Fatal("expected duplicate Add to fail in set mode")
+	}
+	if got := m.Get("a"); !slices.Equal(got, []int{1, 2}) {
+		t.Fatalf("expected [1 2], got %v", got)
+	}
+	if !m.Contains("a", 2) || m.Contains("a", 3) {
+		t.Fatal("unexpected Contains result")
+	}
+}
+
+func TestMultiMap_GetReturnsCopy(t *testing.T) {
+	m := NewMultiMap[string, int](MultiMapList)
+	m.Add("a", 1)
+	got := m.Get("a")
+	got[0] = 42
+	if v := m.Get("a"); v[0] != 1 {
+		t.Fatalf("expected stored value to be unaffected, got %v", v)
+	}
+	if m.Get("missing") != nil {
+		t.Fatal("expected nil for missing key")
+	}
+}
+
+func TestMultiMap_RemoveAllAndLen(t *testing.T) {
+	m := NewMultiMap[string, int](MultiMapList)
+	m.Add("a", 1)
+	m.Add("a", 2)
+	m.Add("b", 3)
+	if m.Len() != 2 {
+		t.Fatalf("expected 2 keys, got %d", m.Len())
+	}
+	if n := m.RemoveAll("a"); n != 2 {
+		t.Fatalf("expected 2 removed values, got %d", n)
+	}
+	if n := m.RemoveAll("a"); n != 0 {
+		t.Fatalf("expected 0 removed values for missing key, got %d", n)
+	}
+	m.Remove("b", 3)
+	if m.Len() != 0 {
+		t.Fatalf("expected empty keys to be dropped, got len %d", m.Len())
+	}
+}
+
+func TestMultiMap_Range(t *testing.T) {
+	m := NewMultiMap[int, int](MultiMapList)
+	m.Add(1, 10)
+	m.Add(1, 11)
+	m.Add(2, 20)
+
+	collected := make(map[int][]int)
+	m.Range(func(k int, values []int) bool {
+		collected[k] = values
+		m.RemoveAll(k) // modifying the map from f must not deadlock
+		return true
+	})
+	if !slices.Equal(collected[1], []int{10, 11}) || !slices.Equal(collected[2], []int{20}) {
+		t.Fatalf("unexpected collected values %v", collected)
+	}
+	if m.Len() != 0 {
+		t.Fatalf("expected len 0, got %d", m.Len())
+	}
+
+	m.Add(1, 1)
+	m.Add(2, 2)
+	times := 0
+	m.Range(func(k int, values []int) bool {
+		times++
+		return false
+	})
+	if times != 1 {
+		t.Fatalf("expected Range to stop after first iteration, got %d iterations", times)
+	}
+	m.Range(nil)
+}
+
+func TestMultiMap_Concurrent(t *testing.T) {
+	m := NewMultiMap[int, int](MultiMapSet)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(v int) {
+			defer wg.Done()
+			m.Add(v%10, v)
+			m.Get(v % 10)
+			m.Count(v % 10)
+		}(i)
+	}
+	wg.Wait()
+	for k := 0; k < 10; k++ {
+		if c := m.Count(k); c != 10 {
+			t.Fatalf("expected 10 values for key %d, got %d", k, c)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package maps
+
+import "time"
+
+// NamespacedKey is the key of a TtlTypedSyncMap shared through Namespace views.
+type NamespacedKey[K comparable] struct {
+	Namespace string
+	Key       K
+}
+
+// Namespace is a view of a shared TtlTypedSyncMap scoped to the keys of one
+// namespace. Views over the same map share its janitor, expiration and
+// memory, but never see each other's entries. Safe for concurrent use.
+//
+// Range, Len and Clear scan the whole shared map, so their cost grows with
+// the total number of entries, not the namespace's.
+type Namespace[K comparable, V any] struct {
+	m    *TtlTypedSyncMap[NamespacedKey[K], V]
+	name string
+}
+
+// NewNamespace returns the view of m scoped to namespace name.
+// Views with the same name over the same map are interchangeable.
+func NewNamespace[K comparable, V any](m *TtlTypedSyncMap[NamespacedKey[K], V], name string) *Namespace[K, V] {
+	return &Namespace[K, V]{
+		m:    m,
+		name: name,
+	}
+}
+
+// Name returns the namespace of the view.
+func (n *Namespace[K, V]) Name() string {
+	return n.name
+}
+
+func (n *Namespace[K, V]) Store(key K, value V) {
+	n.m.Store(n.key(key), value)
+}
+
+func (n *Namespace[K, V]) Load(key K) (V, bool) {
+	return n.m.Load(n.key(key))
+}
+
+func (n *Namespace[K, V]) Delete(key K) {
+	n.m.Delete(n.key(key))
+}
+
+// Len returns the number of live entries in the namespace.
+func (n *Namespace[K, V]) Len() int64 {
+	return n.m.countMatching(n.contains)
+}
+
+// Range calls f for each entry of the namespace until f returns false,
+// prolonging only the entries it visits. As with TtlTypedSyncMap.Range,
+// f must not call into the shared map.
+func (n *Namespace[K, V]) Range(f func(key K, value V) bool) {
+	if f == nil {
+		return
+	}
+	n.m.rangeMatching(n.contains, func(key NamespacedKey[K], value V) bool {
+		return f(key.Key, value)
+	})
+}
+
+// Clear removes every entry of the namespace and returns how many live
+// entries it removed.
+func (n *Namespace[K, V]) Clear() int {
+	return n.m.deleteMatching(n.contains)
+}
+
+// TTL returns the expiration period of the shared map.
+func (n *Namespace[K, V]) TTL() time.Duration {
+	return n.m.TTL()
+}
+
+func (n *Namespace[K, V]) key(key K) NamespacedKey[K] {
+	return NamespacedKey[K]{Namespace: n.name, Key: key}
+}
+
+func (n *Namespace[K, V]) contains(key NamespacedKey[K]) bool {
+	return key.Namespace == n.name
+}
@@ -0,0 +1,130 @@
+package maps
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNamespace_Isolation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	shared := NewTtlTypedSyncMap[NamespacedKey[string], int](ctx, time.Hour, time.Hour)
+	users := NewNamespace[string, int](shared, "users")
+	orders := NewNamespace[string, int](shared, "orders")
+
+	users.Store("a", 1)
+	users.Store("b", 2)
+	orders.Store("a", 10)
+
+	if v, ok := users.Load("a"); !ok || v != 1 {
+		t.Fatalf("expected (1, true), got (%v, %v)", v, ok)
+	}
+	if v, ok := orders.Load("a"); !ok || v != 10 {
+		t.Fatalf("expected (10, true), got (%v, %v)", v, ok)
+	}
+	if users.Len() != 2 || orders.Len() != 1 || shared.Len() != 3 {
+		t.Fatalf("expected lens 2, 1 and 3, got %d, %d and %d", users.Len(), orders.Len(), shared.Len())
+	}
+
+	orders.Delete("a")
+	if _, ok := users.Load("a"); !ok {
+		t.Fatal("expected Delete to be scoped to its namespace")
+	}
+
+	seen := make(map[string]int)
+	users.Range(func(k string, v int) bool {
+		seen[k] = v
+		return true
+	})
+	if len(seen) != 2 || seen["a"] != 1 || seen["b"] != 2 {
+		t.Fatalf("expected map[a:1 b:2], got %v", seen)
+	}
+	users.Range(nil)
+}
+
+func TestNamespace_Clear(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	shared := NewTtlTypedSyncMap[NamespacedKey[int], string](ctx, time.Hour, time.Hour)
+	a := NewNamespace[int, string](shared, "a")
+	b := NewNamespace[int, string](shared, "b")
+	for i := 0; i < 5; i++ {
+		a.Store(i, "a")
+		b.Store(i, "b")
+	}
+
+	if n := a.Clear(); n != 5 {
+		t.Fatalf("expected 5 removed entries, got %d", n)
+	}
+	if a.Len() != 0 || b.Len() != 5 {
+		t.Fatalf("expected lens 0 and 5, got %d and %d", a.Len(), b.Len())
+	}
+	if n := a.Clear(); n != 0 {
+		t.Fatalf("expected clearing an empty namespace to remove nothing, got %d", n)
+	}
+}
+
+func TestNamespace_RangeProlongsOnlyItsEntries(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// the janitor interval is long enough not to interfere
+	shared := NewTtlTypedSyncMap[NamespacedKey[string], int](ctx, 50*time.Millisecond, time.Hour)
+	hot := NewNamespace[string, int](shared, "hot")
+	cold := NewNamespace[string, int](shared, "cold")
+	hot.Store("a", 1)
+	cold.Store("a", 2)
+
+	for i := 0; i < 4; i++ {
+		time.Sleep(20 * time.Millisecond)
+		hot.Range(func(k string, v int) bool { return true })
+	}
+	if hot.Len() != 1 {
+		t.Fatalf("expected ranged entry to stay, got len %d", hot.Len())
+	}
+	if cold.Len() != 0 {
+		t.Fatalf("expected other namespace to expire, got len %d", cold.Len())
+	}
+	if _, ok := cold.Load("a"); ok {
+		t.Fatal("expected other namespace's entry to be expired")
+	}
+}
+
+func TestNamespace_SharedTTL(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	shared := NewTtlTypedSyncMap[NamespacedKey[string], int](ctx, time.Minute, 0)
+	ns := NewNamespace[string, int](shared, "ns")
+	if ns.TTL() != time.Minute || ns.Name() != "ns" {
+		t.Fatalf("expected TTL 1m and name \"ns\", got %v and %q", ns.TTL(), ns.Name())
+	}
+}
+
+func TestNamespace_Concurrent(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	shared := NewTtlTypedSyncMap[NamespacedKey[int], int](ctx, time.Hour, time.Hour)
+	views := []*Namespace[int, int]{
+		NewNamespace[int, int](shared, "a"),
+		NewNamespace[int, int](shared, "b"),
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ns := views[i%2]
+			ns.Store(i, i)
+			ns.Load(i)
+			ns.Len()
+		}(i)
+	}
+	wg.Wait()
+	for _, ns := range views {
+		if ns.Len() != 50 {
+			t.Fatalf("expected 50 entries in %q, got %d", ns.Name(), ns.Len())
+		}
+	}
+}
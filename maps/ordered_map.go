@@ -0,0 +1,226 @@
+package maps
+
+import "sync"
+
+// OrderedMap is a map that iterates in insertion order.
+// Store, Load, Delete, MoveToFront and MoveToBack are O(1).
+// Not safe for concurrent use; see SyncOrderedMap.
+type OrderedMap[K comparable, V any] struct {
+	items map[K]*orderedNode[K, V]
+	// root is a sentinel: root.next is the front, root.prev the back.
+	root orderedNode[K, V]
+}
+
+type orderedNode[K comparable, V any] struct {
+	key        K
+	value      V
+	prev, next *orderedNode[K, V]
+}
+
+// NewOrderedMap returns a new empty OrderedMap.
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	res := &OrderedMap[K, V]{
+		items: make(map[K]*orderedNode[K, V]),
+	}
+	res.root.prev = &res.root
+	res.root.next = &res.root
+	return res
+}
+
+// Store sets the value for key. A new key is appended to the back;
+// an existing key keeps its position.
+func (o *OrderedMap[K, V]) Store(key K, value V) {
+	if n, ok := o.items[key]; ok {
+		n.value = value
+		return
+	}
+	n := &orderedNode[K, V]{key: key, value: value}
+	o.items[key] = n
+	o.insertAfter(n, o.root.prev)
+}
+
+func (o *OrderedMap[K, V]) Load(key K) (V, bool) {
+	n, ok := o.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return n.value, true
+}
+
+func (o *OrderedMap[K, V]) Delete(key K) {
+	n, ok := o.items[key]
+	if !ok {
+		return
+	}
+	delete(o.items, key)
+	o.unlink(n)
+}
+
+func (o *OrderedMap[K, V]) Len() int64 {
+	return int64(len(o.items))
+}
+
+// Range calls f for each entry from front to back until f returns false.
+// The order is fixed when Range starts, so f may modify the map: entries
+// deleted before they are reached are skipped, moved entries are visited
+// once, and entries added during the iteration are not visited.
+func (o *OrderedMap[K, V]) Range(f func(key K, value V) bool) {
+	if f == nil {
+		return
+	}
+
+	nodes := make([]*orderedNode[K, V], 0, len(o.items))
+	for n := o.root.next; n != &o.root; n = n.next {
+		nodes = append(nodes, n)
+	}
+	for _, n := range nodes {
+		if o.items[n.key] != n {
+			continue
+		}
+		if !f(n.key, n.value) {
+			return
+		}
+	}
+}
+
+// Front returns the first entry. The boolean result is false if the map is empty.
+func (o *OrderedMap[K, V]) Front() (K, V, bool) {
+	return o.entry(o.root.next)
+}
+
+// Back returns the last entry. The boolean result is false if the map is empty.
+func (o *OrderedMap[K, V]) Back() (K, V, bool) {
+	return o.entry(o.root.prev)
+}
+
+// MoveToFront moves key to the front. It reports whether key is present.
+func (o *OrderedMap[K, V]) MoveToFront(key K) bool {
+	n, ok := o.items[key]
+	if !ok {
+		return false
+	}
+	o.unlink(n)
+	o.insertAfter(n, &o.root)
+	return true
+}
+
+// MoveToBack moves key to the back. It reports whether key is present.
+func (o *OrderedMap[K, V]) MoveToBack(key K) bool {
+	n, ok := o.items[key]
+	if !ok {
+		return false
+	}
+	o.unlink(n)
+	o.insertAfter(n, o.root.prev)
+	return true
+}
+
+func (o *OrderedMap[K, V]) entry(n *orderedNode[K, V]) (K, V, bool) {
+	if n == &o.root {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+	return n.key, n.value, true
+}
+
+func (o *OrderedMap[K, V]) insertAfter(n, at *orderedNode[K, V]) {
+	n.prev = at
+	n.next = at.next
+	at.next.prev = n
+	at.next = n
+}
+
+func (o *OrderedMap[K, V]) unlink(n *orderedNode[K, V]) {
+	n.prev.next = n.next
+	n.next.prev = n.prev
+	n.prev = nil
+	n.next = nil
+}
+
+// SyncOrderedMap is an OrderedMap guarded by a sync.RWMutex.
+// Safe for concurrent use.
+type SyncOrderedMap[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  *OrderedMap[K, V]
+}
+
+// NewSyncOrderedMap returns a new empty SyncOrderedMap.
+func NewSyncOrderedMap[K comparable, V any]() *SyncOrderedMap[K, V] {
+	return &SyncOrderedMap[K, V]{
+		m: NewOrderedMap[K, V](),
+	}
+}
+
+func (s *SyncOrderedMap[K, V]) Store(key K, value V) {
+	s.mu.Lock()
+	s.m.Store(key, value)
+	s.mu.Unlock()
+}
+
+func (s *SyncOrderedMap[K, V]) Load(key K) (V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Load(key)
+}
+
+func (s *SyncOrderedMap[K, V]) Delete(key K) {
+	s.mu.Lock()
+	s.m.Delete(key)
+	s.mu.Unlock()
+}
+
+func (s *SyncOrderedMap[K, V]) Len() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Len()
+}
+
+// Range calls f for each entry in insertion order until f returns false.
+// It iterates over a copy taken under the lock, so f may modify the map.
+func (s *SyncOrderedMap[K, V]) Range(f func(key K, value V) bool) {
+	if f == nil {
+		return
+	}
+
+	s.mu.RLock()
+	keys := make([]K, 0, len(s.m.items))
+	values := make([]V, 0, len(s.m.items))
+	s.m.Range(func(k K, v V) bool {
+		keys = append(keys, k)
+		values = append(values, v)
+		return true
+	})
+	s.mu.RUnlock()
+
+	for i := range keys {
+		if !f(keys[i], values[i]) {
+			return
+		}
+	}
+}
+
+func (s *SyncOrderedMap[K, V]) Front() (K, V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Front()
+}
+
+func (s *SyncOrderedMap[K, V]) Back() (K, V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Back()
+}
+
+func (s *SyncOrderedMap[K, V]) MoveToFront(key K) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.m.MoveToFront(key)
+}
+
+func (s *SyncOrderedMap[K, V]) MoveToBack(key K) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.m.MoveToBack(key)
+}
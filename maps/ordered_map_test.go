@@ -0,0 +1,200 @@
+package maps
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func orderedKeys[K comparable, V any](r interface {
+	Range(func(K, V) bool)
+}) []K {
+	var keys []K
+	r.Range(func(k K, _ V) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+func TestOrderedMap_InsertionOrder(t *testing.T) {
+	m := NewOrderedMap[string, int]()
+	m.Store("c", 1)
+	m.Store("a", 2)
+	m.Store("b", 3)
+	m.Store("c", 4) // overwrite keeps position
+
+	if got, want := orderedKeys[string, int](m), []string{"c", "a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected order %v, got %v", want, got)
+	}
+	if v, ok := m.Load("c"); !ok || v != 4 {
+		t.Fatalf("expected (4, true), got (%v, %v)", v, ok)
+	}
+	if m.Len() != 3 {
+		t.Fatalf("expected len 3, got %d", m.Len())
+	}
+}
+
+func TestOrderedMap_Delete(t *testing.T) {
+	m := NewOrderedMap[int, string]()
+	for i := 1; i <= 4; i++ {
+		m.Store(i, "v")
+	}
+	m.Delete(2)
+	m.Delete(42)
+	if got, want := orderedKeys[int, string](m), []int{1, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected order %v, got %v", want, got)
+	}
+	if _, ok := m.Load(2); ok {
+		t.Fatal("expected key 2 to be deleted")
+	}
+	m.Store(2, "again")
+	if got, want := orderedKeys[int, string](m), []int{1, 3, 4, 2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected re-inserted key at the back %v, got %v", want, got)
+	}
+}
+
+func TestOrderedMap_MoveToFrontBack(t *testing.T) {
+	m := NewOrderedMap[int, int]()
+	for i := 1; i <= 4; i++ {
+		m.Store(i, i)
+	}
+	if !m.MoveToFront(3) || !m.MoveToBack(1) {
+		t.Fatal("expected moves of present keys to succeed")
+	}
+	if m.MoveToFront(42) || m.MoveToBack(42) {
+		t.Fatal("expected moves of missing keys to fail")
+	}
+	if got, want := orderedKeys[int, int](m), []int{3, 2, 4, 1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected order %v, got %v", want, got)
+	}
+	if k, v, ok := m.Front(); !ok || k != 3 || v != 3 {
+		t.Fatalf("expected front (3, 3, true), got (%v, %v, %v)", k, v, ok)
+	}
+	if k, _, ok := m.Back(); !ok || k != 1 {
+		t.Fatalf("expected back 1, got (%v, %v)", k, ok)
+	}
+}
+
+func TestOrderedMap_Empty(t *testing.T) {
+	m := NewOrderedMap[int, int]()
+	if _, _, ok := m.Front(); ok {
+		t.Fatal("expected Front on empty map to return false")
+	}
+	if _, _, ok := m.Back(); ok {
+		t.Fatal("expected Back on empty map to return false")
+	}
+	m.Range(func(k, v int) bool {
+		t.Fatal("should not iterate over empty map")
+		return true
+	})
+	m.Range(nil)
+}
+
+func TestOrderedMap_Range_DeleteCurrentAndStop(t *testing.T) {
+	m := NewOrderedMap[int, int]()
+	for i := 1; i <= 4; i++ {
+		m.Store(i, i)
+	}
+	m.Range(func(k, v int) bool {
+		if k%2 == 0 {
+			m.Delete(k)
+		}
+		return true
+	})
+	if got, want := orderedKeys[int, int](m), []int{1, 3}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected order %v, got %v", want, got)
+	}
+
+	times := 0
+	m.Range(func(k, v int) bool {
+		times++
+		return false
+	})
+	if times != 1 {
+		t.Fatalf("expected Range to stop after first iteration, got %d iterations", times)
+	}
+}
+
+func TestOrderedMap_Range_DeleteLater(t *testing.T) {
+	m := NewOrderedMap[int, int]()
+	for i := 1; i <= 3; i++ {
+		m.Store(i, i)
+	}
+	var visited []int
+	m.Range(func(k, v int) bool {
+		visited = append(visited, k)
+		if k == 1 {
+			m.Delete(2)
+		}
+		return true
+	})
+	if want := []int{1, 3}; !reflect.DeepEqual(visited, want) {
+		t.Fatalf("expected to visit %v, got %v", want, visited)
+	}
+}
+
+func TestOrderedMap_Range_MoveCurrent(t *testing.T) {
+	m := NewOrderedMap[int, int]()
+	for i := 1; i <= 3; i++ {
+		m.Store(i, i)
+	}
+	var visited []int
+	m.Range(func(k, v int) bool {
+		visited = append(visited, k)
+		m.MoveToBack(k)
+		return len(visited) < 10
+	})
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(visited, want) {
+		t.Fatalf("expected to visit %v, got %v", want, visited)
+	}
+	if got, want := orderedKeys[int, int](m), []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected order %v, got %v", want, got)
+	}
+}
+
+func TestSyncOrderedMap(t *testing.T) {
+	m := NewSyncOrderedMap[int, int]()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(k int) {
+			defer wg.Done()
+			m.Store(k, k)
+			m.Load(k)
+			m.MoveToFront(k)
+			m.MoveToBack(k)
+			m.Front()
+			m.Back()
+			if k%2 == 0 {
+				m.Delete(k)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if m.Len() != 25 {
+		t.Fatalf("expected len 25, got %d", m.Len())
+	}
+
+	seen := 0
+	m.Range(func(k, v int) bool {
+		m.Delete(k) // modifying the map from f must not deadlock
+		seen++
+		return true
+	})
+	if seen != 25 || m.Len() != 0 {
+		t.Fatalf("expected to visit and delete 25 entries, visited %d, len %d", seen, m.Len())
+	}
+	m.Range(nil)
+
+	m.Store(1, 1)
+	m.Store(2, 2)
+	times := 0
+	m.Range(func(k, v int) bool {
+		times++
+		return false
+	})
+	if times != 1 {
+		t.Fatalf("expected Range to stop after first iteration, got %d iterations", times)
+	}
+}
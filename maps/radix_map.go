@@ -0,0 +1,370 @@
+package maps
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RadixMap is a concurrent map with string or byte-slice keys, stored in a
+// radix tree so it can answer prefix queries: LongestPrefix, WalkPrefix and
+// DeletePrefix. Range and WalkPrefix visit keys in lexicographic byte order.
+// Safe for concurrent use.
+//
+// Entries stored with StoreWithTTL expire lazily: they are never returned
+// once expired and are removed by the next Load that finds them, by writes
+// to them, or by DeleteExpired. Until then Len still counts them.
+type RadixMap[K ~string | ~[]byte, V any] struct {
+	mu   sync.RWMutex
+	root *radixNode[V]
+	len  int64
+	now  func() time.Time
+}
+
+type radixNode[V any] struct {
+	// prefix is the label of the edge from the parent; empty for the root.
+	prefix string
+	leaf   *radixLeaf[V]
+	// children are ordered by the first byte of their prefix.
+	children []*radixNode[V]
+}
+
+type radixLeaf[V any] struct {
+	key       string
+	value     V
+	expiresAt time.Time // zero means never
+}
+
+// NewRadixMap returns a new empty RadixMap.
+func NewRadixMap[K ~string | ~[]byte, V any]() *RadixMap[K, V] {
+	return &RadixMap[K, V]{
+		root: &radixNode[V]{},
+		now:  time.Now,
+	}
+}
+
+// Store maps key to value without expiration.
+func (r *RadixMap[K, V]) Store(key K, value V) {
+	r.store(string(key), value, time.Time{})
+}
+
+// StoreWithTTL maps key to value until ttl has passed. A non-positive ttl
+// stores the entry without expiration.
+func (r *RadixMap[K, V]) StoreWithTTL(key K, value V, ttl time.Duration) {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = r.now().Add(ttl)
+	}
+	r.store(string(key), value, expiresAt)
+}
+
+func (r *RadixMap[K, V]) Load(key K) (V, bool) {
+	r.mu.RLock()
+	leaf := r.find(string(key))
+	if leaf == nil {
+		r.mu.RUnlock()
+		var zero V
+		return zero, false
+	}
+	if !r.expired(leaf, r.now()) {
+		v := leaf.value
+		r.mu.RUnlock()
+		return v, true
+	}
+	r.mu.RUnlock()
+
+	r.mu.Lock()
+	// the entry may have been replaced while the lock was released
+	if leaf := r.find(string(key)); leaf != nil && r.expired(leaf, r.now()) {
+		r.delete(string(key))
+	}
+	r.mu.Unlock()
+	var zero V
+	return zero, false
+}
+
+func (r *RadixMap[K, V]) Delete(key K) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.delete(string(key))
+}
+
+// Len returns the number of entries, including expired ones not removed yet.
+func (r *RadixMap[K, V]) Len() int64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.len
+}
+
+// Range calls f for each live entry in key order until f returns false.
+// It iterates over a copy taken under the lock, so f may modify the map.
+func (r *RadixMap[K, V]) Range(f func(key K, value V) bool) {
+	r.WalkPrefix(K(""), f)
+}
+
+// LongestPrefix returns the entry with the longest key that is a prefix
+// of s, if any.
+func (r *RadixMap[K, V]) LongestPrefix(s K) (K, V, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.now()
+	var best *radixLeaf[V]
+	n, search := r.root, string(s)
+	for {
+		if n.leaf != nil && !r.expired(n.leaf, now) {
+			best = n.leaf
+		}
+		if search == "" {
+			break
+		}
+		child, _ := n.child(search[0])
+		if child == nil || !strings.HasPrefix(search, child.prefix) {
+			break
+		}
+		n, search = child, search[len(child.prefix):]
+	}
+
+	if best == nil {
+		var zero V
+		return K(""), zero, false
+	}
+	return K(best.key), best.value, true
+}
+
+// WalkPrefix calls f for each live entry whose key starts with prefix, in
+// key order, until f returns false. It iterates over a copy taken under
+// the lock, so f may modify the map.
+func (r *RadixMap[K, V]) WalkPrefix(prefix K, f func(key K, value V) bool) {
+	if f == nil {
+		return
+	}
+
+	r.mu.RLock()
+	var leaves []radixLeaf[V]
+	if _, n := r.findPrefix(string(prefix)); n != nil {
+		now := r.now()
+		n.walk(func(leaf *radixLeaf[V]) {
+			if !r.expired(leaf, now) {
+				leaves = append(leaves, *leaf)
+			}
+		})
+	}
+	r.mu.RUnlock()
+
+	for _, leaf := range leaves {
+		if !f(K(leaf.key), leaf.value) {
+			return
+		}
+	}
+}
+
+// DeletePrefix removes every entry whose key starts with prefix and returns
+// how many live entries it removed. An empty prefix clears the map.
+func (r *RadixMap[K, V]) DeletePrefix(prefix K) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	parent, n := r.findPrefix(string(prefix))
+	if n == nil {
+		return 0
+	}
+
+	now := r.now()
+	removed, live := 0, 0
+	n.walk(func(leaf *radixLeaf[V]) {
+		removed++
+		if !r.expired(leaf, now) {
+			live++
+		}
+	})
+	r.len -= int64(removed)
+
+	if parent == nil {
+		r.root = &radixNode[V]{}
+		return live
+	}
+	parent.removeChild(n)
+	r.compact(parent)
+	return live
+}
+
+// DeleteExpired removes every expired entry and returns how many it removed.
+func (r *RadixMap[K, V]) DeleteExpired() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	var keys []string
+	r.root.walk(func(leaf *radixLeaf[V]) {
+		if r.expired(leaf, now) {
+			keys = append(keys, leaf.key)
+		}
+	})
+	for _, k := range keys {
+		r.delete(k)
+	}
+	return len(keys)
+}
+
+func (r *RadixMap[K, V]) expired(leaf *radixLeaf[V], now time.Time) bool {
+	return !leaf.expiresAt.IsZero() && now.After(leaf.expiresAt)
+}
+
+func (r *RadixMap[K, V]) store(key string, value V, expiresAt time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	leaf := &radixLeaf[V]{key: key, value: value, expiresAt: expiresAt}
+	n, search := r.root, key
+	for {
+		if search == "" {
+			if n.leaf == nil {
+				r.len++
+			}
+			n.leaf = leaf
+			return
+		}
+
+		child, i := n.child(search[0])
+		if child == nil {
+			n.addChild(&radixNode[V]{prefix: search, leaf: leaf})
+			r.len++
+			return
+		}
+		common := commonPrefixLen(search, child.prefix)
+		if common == len(child.prefix) {
+			n, search = child, search[common:]
+			continue
+		}
+
+		// split the edge at the end of the common prefix
+		split := &radixNode[V]{prefix: search[:common]}
+		n.children[i] = split
+		child.prefix = child.prefix[common:]
+		split.addChild(child)
+		if search = search[common:]; search == "" {
+			split.leaf = leaf
+		} else {
+			split.addChild(&radixNode[V]{prefix: search, leaf: leaf})
+		}
+		r.len++
+		return
+	}
+}
+
+// find returns the leaf stored under key. Callers must hold r.mu.
+func (r *RadixMap[K, V]) find(key string) *radixLeaf[V] {
+	n, search := r.root, key
+	for search != "" {
+		child, _ := n.child(search[0])
+		if child == nil || !strings.HasPrefix(search, child.prefix) {
+			return nil
+		}
+		n, search = child, search[len(child.prefix):]
+	}
+	return n.leaf
+}
+
+// findPrefix returns the node holding every key that starts with prefix,
+// and its parent, which is nil for the root. Callers must hold r.mu.
+func (r *RadixMap[K, V]) findPrefix(prefix string) (parent, n *radixNode[V]) {
+	n, search := r.root, prefix
+	for search != "" {
+		child, _ := n.child(search[0])
+		switch {
+		case child == nil:
+			return nil, nil
+		case strings.HasPrefix(search, child.prefix):
+			parent, n, search = n, child, search[len(child.prefix):]
+		case strings.HasPrefix(child.prefix, search):
+			// prefix ends inside the edge to child
+			return n, child
+		default:
+			return nil, nil
+		}
+	}
+	return parent, n
+}
+
+// delete removes key and merges the nodes it leaves redundant.
+// Callers must hold r.mu.
+func (r *RadixMap[K, V]) delete(key string) {
+	var parent *radixNode[V]
+	n, search := r.root, key
+	for search != "" {
+		child, _ := n.child(search[0])
+		if child == nil || !strings.HasPrefix(search, child.prefix) {
+			return
+		}
+		parent, n, search = n, child, search[len(child.prefix):]
+	}
+	if n.leaf == nil {
+		return
+	}
+	n.leaf = nil
+	r.len--
+
+	if parent == nil {
+		return
+	}
+	if len(n.children) == 0 {
+		parent.removeChild(n)
+		r.compact(parent)
+		return
+	}
+	r.compact(n)
+}
+
+// compact merges n with its only child when n holds no entry.
+func (r *RadixMap[K, V]) compact(n *radixNode[V]) {
+	if n == r.root || n.leaf != nil || len(n.children) != 1 {
+		return
+	}
+	child := n.children[0]
+	n.prefix += child.prefix
+	n.leaf = child.leaf
+	n.children = child.children
+}
+
+func (n *radixNode[V]) child(b byte) (*radixNode[V], int) {
+	i, ok := slices.BinarySearchFunc(n.children, b, func(c *radixNode[V], b byte) int {
+		return int(c.prefix[0]) - int(b)
+	})
+	if !ok {
+		return nil, i
+	}
+	return n.children[i], i
+}
+
+func (n *radixNode[V]) addChild(c *radixNode[V]) {
+	_, i := n.child(c.prefix[0])
+	n.children = slices.Insert(n.children, i, c)
+}
+
+func (n *radixNode[V]) removeChild(c *radixNode[V]) {
+	if _, i := n.child(c.prefix[0]); i < len(n.children) && n.children[i] == c {
+		n.children = slices.Delete(n.children, i, i+1)
+	}
+}
+
+// walk calls f for every leaf under n in key order.
+func (n *radixNode[V]) walk(f func(leaf *radixLeaf[V])) {
+	if n.leaf != nil {
+		f(n.leaf)
+	}
+	for _, c := range n.children {
+		c.walk(f)
+	}
+}
+
+func commonPrefixLen(a, b string) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
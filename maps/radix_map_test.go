@@ -0,0 +1,365 @@
+package maps
+
+import (
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type radixEntry struct {
+	key   string
+	value int
+}
+
+func collectRadix(walk func(f func(key string, value int) bool)) []radixEntry {
+	var res []radixEntry
+	walk(func(k string, v int) bool {
+		res = append(res, radixEntry{k, v})
+		return true
+	})
+	return res
+}
+
+// checkRadixInvariants verifies that the tree is compact: every non-root
+// node without an entry has at least two children, children are ordered
+// and leaves sit at the end of their key's path.
+func checkRadixInvariants[K ~string | ~[]byte, V any](t *testing.T, r *RadixMap[K, V]) {
+	t.Helper()
+	var count int64
+	var check func(n *radixNode[V], path string)
+	check = func(n *radixNode[V], path string) {
+		if n != r.root {
+			if n.prefix == "" {
+				t.Fatalf("empty edge under %q", path)
+			}
+			if n.leaf == nil && len(n.children) < 2 {
+				t.Fatalf("redundant node at %q", path+n.prefix)
+			}
+		}
+		path += n.prefix
+		if n.leaf != nil {
+			count++
+			if n.leaf.key != path {
+				t.Fatalf("leaf %q stored at %q", n.leaf.key, path)
+			}
+		}
+		for i, c := range n.children {
+			if i > 0 && n.children[i-1].prefix[0] >= c.prefix[0] {
+				t.Fatalf("children of %q out of order", path)
+			}
+			check(c, path)
+		}
+	}
+	check(r.root, "")
+	if count != r.len {
+		t.Fatalf("expected len %d to match %d leaves", r.len, count)
+	}
+}
+
+func TestRadixMap_Basic(t *testing.T) {
+	r := NewRadixMap[string, int]()
+	for i, k := range []string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus", "r", ""} {
+		r.Store(k, i)
+	}
+	checkRadixInvariants(t, r)
+	if r.Len() != 9 {
+		t.Fatalf("expected len 9, got %d", r.Len())
+	}
+	if v, ok := r.Load("rubicon"); !ok || v != 5 {
+		t.Fatalf("expected (5, true), got (%v, %v)", v, ok)
+	}
+	if v, ok := r.Load(""); !ok || v != 8 {
+		t.Fatalf("expected empty key to be stored, got (%v, %v)", v, ok)
+	}
+	for _, k := range []string{"rom", "rubiconx", "x", "ro"} {
+		if _, ok := r.Load(k); ok {
+			t.Fatalf("expected %q to be absent", k)
+		}
+	}
+
+	r.Store("ruber", 42)
+	if v, _ := r.Load("ruber"); v != 42 || r.Len() != 9 {
+		t.Fatalf("expected overwrite, got %d with len %d", v, r.Len())
+	}
+
+	for _, k := range []string{"romane", "rubicon", "r", "missing", "rom"} {
+		r.Delete(k)
+		checkRadixInvariants(t, r)
+	}
+	if r.Len() != 6 {
+		t.Fatalf("expected len 6, got %d", r.Len())
+	}
+	if _, ok := r.Load("romanus"); !ok {
+		t.Fatal("expected sibling of deleted key to stay")
+	}
+}
+
+func TestRadixMap_OrderedRange(t *testing.T) {
+	r := NewRadixMap[string, int]()
+	keys := []string{"b", "abc", "a", "ab", "ba", "c", "abd"}
+	for i, k := range keys {
+		r.Store(k, i)
+	}
+	var got []string
+	for _, e := range collectRadix(r.Range) {
+		got = append(got, e.key)
+	}
+	slices.Sort(keys)
+	if !slices.Equal(got, keys) {
+		t.Fatalf("expected %v, got %v", keys, got)
+	}
+
+	r.Range(func(k string, v int) bool {
+		r.Delete(k) // modifying the map from f must not deadlock
+		return true
+	})
+	if r.Len() != 0 {
+		t.Fatalf("expected empty map, got len %d", r.Len())
+	}
+	r.Range(nil)
+}
+
+func TestRadixMap_LongestPrefix(t *testing.T) {
+	r := NewRadixMap[string, string]()
+	r.Store("/", "root")
+	r.Store("/api", "api")
+	r.Store("/api/v1/users", "users")
+
+	cases := map[string]string{
+		"/":                 "/",
+		"/index.html":       "/",
+		"/api":              "/api",
+		"/api/v1":           "/api",
+		"/api/v1/users":     "/api/v1/users",
+		"/api/v1/users/42":  "/api/v1/users",
+		"/apiary":           "/api",
+		"/api/v1/usersList": "/api/v1/users",
+	}
+	for s, want := range cases {
+		k, _, ok := r.LongestPrefix(s)
+		if !ok || k != want {
+			t.Fatalf("LongestPrefix(%q): expected %q, got (%q, %v)", s, want, k, ok)
+		}
+	}
+	if _, _, ok := r.LongestPrefix("api"); ok {
+		t.Fatal("expected no match")
+	}
+}
+
+func TestRadixMap_WalkPrefix(t *testing.T) {
+	r := NewRadixMap[string, int]()
+	for i, k := range []string{"team", "test", "tester", "toast", "tea"} {
+		r.Store(k, i)
+	}
+
+	walk := func(prefix string) []string {
+		var res []string
+		r.WalkPrefix(prefix, func(k string, v int) bool {
+			res = append(res, k)
+			return true
+		})
+		return res
+	}
+	if got := walk("te"); !slices.Equal(got, []string{"tea", "team", "test", "tester"}) {
+		t.Fatalf("unexpected walk of \"te\": %v", got)
+	}
+	// the prefix ends inside an edge
+	if got := walk("tes"); !slices.Equal(got, []string{"test", "tester"}) {
+		t.Fatalf("unexpected walk of \"tes\": %v", got)
+	}
+	if got := walk("x"); len(got) != 0 {
+		t.Fatalf("expected no keys, got %v", got)
+	}
+	if got := walk("testers"); len(got) != 0 {
+		t.Fatalf("expected no keys, got %v", got)
+	}
+
+	times := 0
+	r.WalkPrefix("t", func(k string, v int) bool {
+		times++
+		return false
+	})
+	if times != 1 {
+		t.Fatalf("expected WalkPrefix to stop after first iteration, got %d iterations", times)
+	}
+}
+
+func TestRadixMap_DeletePrefix(t *testing.T) {
+	r := NewRadixMap[string, int]()
+	for i, k := range []string{"team", "test", "tester", "toast", "tea"} {
+		r.Store(k, i)
+	}
+
+	if n := r.DeletePrefix("tes"); n != 2 {
+		t.Fatalf("expected 2 removed entries, got %d", n)
+	}
+	checkRadixInvariants(t, r)
+	if _, ok := r.Load("test"); ok {
+		t.Fatal("expected \"test\" to be removed")
+	}
+	if n := r.DeletePrefix("nothing"); n != 0 {
+		t.Fatalf("expected nothing removed, got %d", n)
+	}
+	if n := r.DeletePrefix("tea"); n != 2 {
+		t.Fatalf("expected 2 removed entries, got %d", n)
+	}
+	checkRadixInvariants(t, r)
+	if got := collectRadix(r.Range); len(got) != 1 || got[0].key != "toast" {
+		t.Fatalf("expected only \"toast\" to remain, got %v", got)
+	}
+
+	if n := r.DeletePrefix(""); n != 1 || r.Len() != 0 {
+		t.Fatalf("expected empty prefix to clear the map, removed %d, len %d", n, r.Len())
+	}
+	checkRadixInvariants(t, r)
+}
+
+func TestRadixMap_TTL(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	r := NewRadixMap[string, int]()
+	r.now = clock.Now
+
+	r.StoreWithTTL("/tmp", 1, time.Minute)
+	r.StoreWithTTL("/tmp/a", 2, time.Hour)
+	r.Store("/", 3)
+	r.StoreWithTTL("/zero", 4, 0)
+
+	clock.Advance(2 * time.Minute)
+	if _, ok := r.Load("/tmp"); ok {
+		t.Fatal("expected expired entry")
+	}
+	checkRadixInvariants(t, r)
+	if r.Len() != 3 {
+		t.Fatalf("expected Load to remove the expired entry, got len %d", r.Len())
+	}
+	if k, _, _ := r.LongestPrefix("/tmp/b"); k != "/" {
+		t.Fatalf("expected LongestPrefix to skip expired entries, got %q", k)
+	}
+
+	r.StoreWithTTL("/tmp", 5, time.Minute)
+	clock.Advance(2 * time.Minute)
+	if got := collectRadix(r.Range); len(got) != 3 {
+		t.Fatalf("expected expired entry to be skipped, got %v", got)
+	}
+	if n := r.DeletePrefix("/tmp"); n != 1 {
+		t.Fatalf("expected 1 live entry removed, got %d", n)
+	}
+
+	r.StoreWithTTL("/a", 6, time.Minute)
+	r.StoreWithTTL("/b", 7, time.Minute)
+	clock.Advance(2 * time.Minute)
+	if n := r.DeleteExpired(); n != 2 {
+		t.Fatalf("expected 2 expired entries removed, got %d", n)
+	}
+	checkRadixInvariants(t, r)
+	if r.Len() != 2 {
+		t.Fatalf("expected len 2, got %d", r.Len())
+	}
+}
+
+func TestRadixMap_ByteKeys(t *testing.T) {
+	r := NewRadixMap[[]byte, int]()
+	r.Store([]byte("10.0.0.0"), 1)
+	r.Store([]byte("10.0.1.0"), 2)
+	key := []byte("10.0.0.0")
+	key[0] = '2' // keys are copied on Store
+	if v, ok := r.Load([]byte("10.0.0.0")); !ok || v != 1 {
+		t.Fatalf("expected (1, true), got (%v, %v)", v, ok)
+	}
+
+	k, v, ok := r.LongestPrefix([]byte("10.0.1.0/24"))
+	if !ok || string(k) != "10.0.1.0" || v != 2 {
+		t.Fatalf("expected (10.0.1.0, 2, true), got (%s, %v, %v)", k, v, ok)
+	}
+	n := 0
+	r.WalkPrefix([]byte("10.0."), func(k []byte, v int) bool {
+		n++
+		return true
+	})
+	if n != 2 {
+		t.Fatalf("expected 2 keys, got %d", n)
+	}
+}
+
+func TestRadixMap_RandomizedAgainstReference(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	r := NewRadixMap[string, int]()
+	ref := make(map[string]int)
+	randKey := func() string {
+		b := make([]byte, rnd.IntN(6))
+		for i := range b {
+			b[i] = "abc"[rnd.IntN(3)]
+		}
+		return string(b)
+	}
+
+	for i := 0; i < 5000; i++ {
+		k := randKey()
+		switch rnd.IntN(10) {
+		case 0:
+			want := 0
+			for rk := range ref {
+				if strings.HasPrefix(rk, k) {
+					delete(ref, rk)
+					want++
+				}
+			}
+			if got := r.DeletePrefix(k); got != want {
+				t.Fatalf("DeletePrefix(%q): expected %d, got %d", k, want, got)
+			}
+		case 1, 2, 3:
+			delete(ref, k)
+			r.Delete(k)
+		case 4:
+			var want string
+			found := false
+			for rk := range ref {
+				if strings.HasPrefix(k, rk) && (!found || len(rk) > len(want)) {
+					want, found = rk, true
+				}
+			}
+			got, _, ok := r.LongestPrefix(k)
+			if ok != found || got != want {
+				t.Fatalf("LongestPrefix(%q): expected (%q, %v), got (%q, %v)", k, want, found, got, ok)
+			}
+		default:
+			ref[k] = i
+			r.Store(k, i)
+		}
+	}
+	checkRadixInvariants(t, r)
+
+	var want []radixEntry
+	for k, v := range ref {
+		want = append(want, radixEntry{k, v})
+	}
+	sort.Slice(want, func(i, j int) bool { return want[i].key < want[j].key })
+	if got := collectRadix(r.Range); !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestRadixMap_Concurrent(t *testing.T) {
+	r := NewRadixMap[string, int]()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			k := string(rune('a'+i%5)) + string(rune('a'+i%7))
+			r.StoreWithTTL(k, i, time.Hour)
+			r.Load(k)
+			r.LongestPrefix(k + "x")
+			r.WalkPrefix(k[:1], func(string, int) bool { return true })
+			if i%10 == 0 {
+				r.DeletePrefix(k)
+			}
+		}(i)
+	}
+	wg.Wait()
+	checkRadixInvariants(t, r)
+}
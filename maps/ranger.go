@@ -0,0 +1,7 @@
+package maps
+
+// Ranger is implemented by the map types of this package: Range calls f
+// for each entry until f returns false.
+type Ranger[K comparable, V any] interface {
+	Range(f func(key K, value V) bool)
+}
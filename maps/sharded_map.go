@@ -0,0 +1,156 @@
+package maps
+
+import (
+	"hash/maphash"
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+const defaultShardCount = 32
+
+// ShardedMap is a concurrent map split into a fixed number of shards,
+// each protected by its own sync.RWMutex.
+//
+// Unlike TypedSyncMap, which is built on sync.Map and favours
+// write-once/read-many keys, ShardedMap keeps contention low for
+// write-heavy workloads as long as keys spread evenly across shards.
+type ShardedMap[K comparable, V any] struct {
+	shards []shard[K, V]
+	mask   uint64
+	hasher func(K) uint64
+	count  atomic.Int64
+}
+
+type shard[K comparable, V any] struct {
+	mu    sync.RWMutex
+	items map[K]V
+}
+
+// NewShardedMap returns an empty ShardedMap.
+//
+// shardCount is rounded up to a power of two; values <= 0 fall back to a
+// default of 32. hasher maps keys to shards; if nil, keys are hashed with
+// hash/maphash.
+func NewShardedMap[K comparable, V any](shardCount int, hasher func(K) uint64) *ShardedMap[K, V] {
+	if shardCount <= 0 {
+		shardCount = defaultShardCount
+	}
+	shardCount = 1 << bits.Len(uint(shardCount-1))
+
+	if hasher == nil {
+		seed := maphash.MakeSeed()
+		hasher = func(key K) uint64 {
+			return maphash.Comparable(seed, key)
+		}
+	}
+
+	res := &ShardedMap[K, V]{
+		shards: make([]shard[K, V], shardCount),
+		mask:   uint64(shardCount - 1),
+		hasher: hasher,
+	}
+	for i := range res.shards {
+		res.shards[i].items = make(map[K]V)
+	}
+	return res
+}
+
+func (s *ShardedMap[K, V]) shardFor(key K) *shard[K, V] {
+	return &s.shards[s.hasher(key)&s.mask]
+}
+
+func (s *ShardedMap[K, V]) Store(key K, value V) {
+	sh := s.shardFor(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	// the count is updated under the shard lock so that a concurrent Delete
+	// of the same key cannot apply its decrement first
+	if _, existed := sh.items[key]; !existed {
+		s.count.Add(1)
+	}
+	sh.items[key] = value
+}
+
+func (s *ShardedMap[K, V]) Load(key K) (V, bool) {
+	sh := s.shardFor(key)
+	sh.mu.RLock()
+	v, ok := sh.items[key]
+	sh.mu.RUnlock()
+	return v, ok
+}
+
+func (s *ShardedMap[K, V]) Delete(key K) {
+	sh := s.shardFor(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if _, existed := sh.items[key]; existed {
+		s.count.Add(-1)
+		delete(sh.items, key)
+	}
+}
+
+func (s *ShardedMap[K, V]) Len() int64 {
+	return s.count.Load()
+}
+
+// Range calls f for each entry, shard by shard, until f returns false.
+// Each shard is copied before f is called, so f may modify the map.
+func (s *ShardedMap[K, V]) Range(f func(key K, value V) bool) {
+	if f == nil {
+		return
+	}
+
+	var keys []K
+	var values []V
+	for i := range s.shards {
+		sh := &s.shards[i]
+		keys, values = keys[:0], values[:0]
+		sh.mu.RLock()
+		for k, v := range sh.items {
+			keys = append(keys, k)
+			values = append(values, v)
+		}
+		sh.mu.RUnlock()
+
+		for j := range keys {
+			if !f(keys[j], values[j]) {
+				return
+			}
+		}
+	}
+}
+
+// Update atomically replaces the value for key with fn(old, ok) and returns
+// the stored value. fn is called exactly once while the key's shard is locked
+// and must not access the map.
+func (s *ShardedMap[K, V]) Update(key K, fn func(old V, ok bool) V) V {
+	v, _ := s.Compute(key, func(old V, ok bool) (V, bool) {
+		return fn(old, ok), true
+	})
+	return v
+}
+
+// Compute is like Update, but deletes the key if fn returns keep == false.
+// It returns the resulting value and whether the key is present afterwards.
+func (s *ShardedMap[K, V]) Compute(key K, fn func(old V, ok bool) (value V, keep bool)) (V, bool) {
+	sh := s.shardFor(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	old, existed := sh.items[key]
+	value, keep := fn(old, existed)
+	if !keep {
+		if existed {
+			s.count.Add(-1)
+			delete(sh.items, key)
+		}
+		var zero V
+		return zero, false
+	}
+	if !existed {
+		s.count.Add(1)
+	}
+	sh.items[key] = value
+	return value, true
+}
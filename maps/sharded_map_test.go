@@ -0,0 +1,231 @@
+package maps
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestNewShardedMap_ShardCount(t *testing.T) {
+	cases := []struct {
+		in, want int
+	}{
+		{0, defaultShardCount},
+		{-3, defaultShardCount},
+		{1, 1},
+		{5, 8},
+		{16, 16},
+	}
+	for _, c := range cases {
+		m := NewShardedMap[int, int](c.in, nil)
+		if got := len(m.shards); got != c.want {
+			t.Errorf("NewShardedMap(%d) has %d shards, want %d", c.in, got, c.want)
+		}
+	}
+}
+
+func TestShardedMap_StoreLoadDelete(t *testing.T) {
+	m := NewShardedMap[string, int](4, nil)
+	m.Store("a", 1)
+	m.Store("b", 2)
+	m.Store("a", 3)
+
+	if v, ok := m.Load("a"); !ok || v != 3 {
+		t.Fatalf("expected (3, true), got (%v, %v)", v, ok)
+	}
+	if m.Len() != 2 {
+		t.Fatalf("expected len 2, got %d", m.Len())
+	}
+
+	m.Delete("a")
+	m.Delete("a")
+	if _, ok := m.Load("a"); ok {
+		t.Fatal("expected key to be deleted")
+	}
+	if m.Len() != 1 {
+		t.Fatalf("expected len 1 after delete, got %d", m.Len())
+	}
+}
+
+func TestShardedMap_CustomHasher(t *testing.T) {
+	var calls atomic.Int64
+	m := NewShardedMap[int, string](4, func(k int) uint64 {
+		calls.Add(1)
+		return uint64(k)
+	})
+	m.Store(6, "x")
+	if len(m.shards[2].items) != 1 {
+		t.Fatalf("expected key 6 in shard 2")
+	}
+	if calls.Load() == 0 {
+		t.Fatal("expected custom hasher to be used")
+	}
+}
+
+func TestShardedMap_Range(t *testing.T) {
+	m := NewShardedMap[int, int](4, nil)
+	for i := 0; i < 100; i++ {
+		m.Store(i, i*i)
+	}
+
+	collected := make(map[int]int)
+	m.Range(func(k, v int) bool {
+		collected[k] = v
+		m.Delete(k) // modifying the map from f must not deadlock
+		return true
+	})
+	if len(collected) != 100 {
+		t.Fatalf("expected 100 entries, got %d", len(collected))
+	}
+	if collected[7] != 49 {
+		t.Fatalf("expected 7 -> 49, got %d", collected[7])
+	}
+	if m.Len() != 0 {
+		t.Fatalf("expected len 0, got %d", m.Len())
+	}
+
+	m.Store(1, 1)
+	m.Store(2, 2)
+	times := 0
+	m.Range(func(k, v int) bool {
+		times++
+		return false
+	})
+	if times != 1 {
+		t.Fatalf("expected Range to stop after first iteration, got %d iterations", times)
+	}
+	m.Range(nil)
+}
+
+func TestShardedMap_UpdateCompute(t *testing.T) {
+	m := NewShardedMap[int, int](8, nil)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m.Update(j%5, func(old int, _ bool) int { return old + 1 })
+			}
+		}()
+	}
+	wg.Wait()
+	for k := 0; k < 5; k++ {
+		if v, _ := m.Load(k); v != 1000 {
+			t.Fatalf("expected counter %d to be 1000, got %d", k, v)
+		}
+	}
+
+	v, ok := m.Compute(0, func(old int, ok bool) (int, bool) { return 0, false })
+	if ok || v != 0 {
+		t.Fatalf("expected deletion, got (%d, %v)", v, ok)
+	}
+	if m.Len() != 4 {
+		t.Fatalf("expected len 4, got %d", m.Len())
+	}
+	v, ok = m.Compute(0, func(old int, ok bool) (int, bool) { return 0, false })
+	if ok || m.Len() != 4 {
+		t.Fatalf("expected no-op on absent key, got (%d, %v), len %d", v, ok, m.Len())
+	}
+}
+
+func TestShardedMap_ConcurrentAccess(t *testing.T) {
+	m := NewShardedMap[int, string](0, nil)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(k int) {
+			defer wg.Done()
+			m.Store(k, "v")
+			m.Load(k)
+			m.Delete(k)
+		}(i)
+	}
+	wg.Wait()
+	if m.Len() != 0 {
+		t.Fatalf("expected len 0 after concurrent store/delete, got %d", m.Len())
+	}
+}
+
+func TestShardedMap_LenNeverNegative(t *testing.T) {
+	m := NewShardedMap[int, int](4, nil)
+	var stop atomic.Bool
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !stop.Load() {
+				m.Store(0, 0)
+				m.Delete(0)
+			}
+		}()
+	}
+	for i := 0; i < 100_000; i++ {
+		if n := m.Len(); n < 0 || n > 1 {
+			stop.Store(true)
+			wg.Wait()
+			t.Fatalf("expected len 0 or 1, got %d", n)
+		}
+	}
+	stop.Store(true)
+	wg.Wait()
+}
+
+func TestShardedMap_ComputePanicUnlocksShard(t *testing.T) {
+	m := NewShardedMap[int, int](1, nil)
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected panic from fn to propagate")
+			}
+		}()
+		m.Compute(1, func(int, bool) (int, bool) { panic("boom") })
+	}()
+	m.Store(1, 1)
+	if v, ok := m.Load(1); !ok || v != 1 {
+		t.Fatalf("expected (1, true), got (%d, %v)", v, ok)
+	}
+}
+
+type benchMap interface {
+	Store(key int, value int)
+	Load(key int) (int, bool)
+}
+
+// benchmarkReadWrite runs a mixed workload where writePercent of the
+// operations are stores and the rest are loads over a fixed key space.
+func benchmarkReadWrite(b *testing.B, m benchMap, writePercent int) {
+	const keys = 1 << 12
+	for i := 0; i < keys; i++ {
+		m.Store(i, i)
+	}
+	var seed atomic.Uint64
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		x := seed.Add(0x9E3779B97F4A7C15)
+		for pb.Next() {
+			x ^= x << 13
+			x ^= x >> 7
+			x ^= x << 17
+			k := int(x % keys)
+			if int(x>>32%100) < writePercent {
+				m.Store(k, k)
+			} else {
+				m.Load(k)
+			}
+		}
+	})
+}
+
+func BenchmarkReadWriteRatio(b *testing.B) {
+	for _, writes := range []int{0, 10, 50, 90} {
+		b.Run(fmt.Sprintf("TypedSyncMap/writes=%d%%", writes), func(b *testing.B) {
+			benchmarkReadWrite(b, NewTypedSyncMap[int, int](), writes)
+		})
+		b.Run(fmt.Sprintf("ShardedMap/writes=%d%%", writes), func(b *testing.B) {
+			benchmarkReadWrite(b, NewShardedMap[int, int](0, nil), writes)
+		})
+	}
+}
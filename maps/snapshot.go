@@ -0,0 +1,35 @@
+package maps
+
+import stdmaps "maps"
+
+// Snapshot is an immutable, read-only view of a map's contents at a
+// single point in time. It is safe for concurrent use.
+type Snapshot[K comparable, V any] struct {
+	items map[K]V
+}
+
+func (s Snapshot[K, V]) Load(key K) (V, bool) {
+	v, ok := s.items[key]
+	return v, ok
+}
+
+func (s Snapshot[K, V]) Len() int64 {
+	return int64(len(s.items))
+}
+
+func (s Snapshot[K, V]) Range(f func(key K, value V) bool) {
+	if f == nil {
+		return
+	}
+
+	for k, v := range s.items {
+		if !f(k, v) {
+			return
+		}
+	}
+}
+
+// ToMap returns a mutable copy of the snapshot.
+func (s Snapshot[K, V]) ToMap() map[K]V {
+	return stdmaps.Clone(s.items)
+}
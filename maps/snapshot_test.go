@@ -0,0 +1,43 @@
+package maps
+
+import "testing"
+
+func TestSnapshot_ZeroValue(t *testing.T) {
+	var s Snapshot[int, string]
+	if _, ok := s.Load(1); ok {
+		t.Fatal("expected zero Snapshot to be empty")
+	}
+	if s.Len() != 0 {
+		t.Fatalf("expected len 0, got %d", s.Len())
+	}
+	s.Range(func(k int, v string) bool {
+		t.Fatal("should not iterate over zero Snapshot")
+		return true
+	})
+	if m := s.ToMap(); len(m) != 0 {
+		t.Fatalf("expected empty map, got %v", m)
+	}
+}
+
+func TestSnapshot_ToMapIsCopy(t *testing.T) {
+	s := Snapshot[int, string]{items: map[int]string{1: "a", 2: "b"}}
+	m := s.ToMap()
+	m[3] = "c"
+	delete(m, 1)
+	if s.Len() != 2 {
+		t.Fatalf("expected snapshot to be unaffected, got len %d", s.Len())
+	}
+	if v, ok := s.Load(1); !ok || v != "a" {
+		t.Fatalf("expected (\"a\", true), got (%v, %v)", v, ok)
+	}
+
+	times := 0
+	s.Range(func(k int, v string) bool {
+		times++
+		return false
+	})
+	if times != 1 {
+		t.Fatalf("expected Range to stop after first iteration, got %d iterations", times)
+	}
+	s.Range(nil)
+}
@@ -0,0 +1,371 @@
+package maps
+
+import (
+	"cmp"
+	"iter"
+	"math/rand/v2"
+	"sync"
+)
+
+const (
+	skipListMaxLevel = 32
+	// skipListP is the inverse probability of promoting a node one level up.
+	skipListP = 4
+)
+
+// SortedMap is a map ordered by key, implemented as an indexable skip list.
+//
+// Store, Load, Delete, Floor, Ceiling, Rank and Select are O(log n) on
+// average; Min is O(1). Not safe for concurrent use; see SyncSortedMap.
+type SortedMap[K cmp.Ordered, V any] struct {
+	head   *skipNode[K, V]
+	level  int
+	length int
+}
+
+type skipNode[K cmp.Ordered, V any] struct {
+	key   K
+	value V
+	next  []*skipNode[K, V]
+	// span[i] is the number of positions between this node and next[i];
+	// for the last node on a level it counts the remaining nodes.
+	span []int
+}
+
+// NewSortedMap returns a new empty SortedMap.
+func NewSortedMap[K cmp.Ordered, V any]() *SortedMap[K, V] {
+	return &SortedMap[K, V]{
+		head: &skipNode[K, V]{
+			next: make([]*skipNode[K, V], skipListMaxLevel),
+			span: make([]int, skipListMaxLevel),
+		},
+		level: 1,
+	}
+}
+
+// findPath returns, for every level, the last node with a key less than key
+// and that node's rank (the number of nodes before and including it).
+func (s *SortedMap[K, V]) findPath(key K) (update [skipListMaxLevel]*skipNode[K, V], rank [skipListMaxLevel]int) {
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		if i < s.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.next[i] != nil && cmp.Less(x.next[i].key, key) {
+			rank[i] += x.span[i]
+			x = x.next[i]
+		}
+		update[i] = x
+	}
+	return update, rank
+}
+
+func (s *SortedMap[K, V]) Store(key K, value V) {
+	update, rank := s.findPath(key)
+	if n := update[0].next[0]; n != nil && cmp.Compare(n.key, key) == 0 {
+		n.value = value
+		return
+	}
+
+	lvl := randomSkipLevel()
+	if lvl > s.level {
+		for i := s.level; i < lvl; i++ {
+			rank[i] = 0
+			update[i] = s.head
+			s.head.span[i] = s.length
+		}
+		s.level = lvl
+	}
+
+	n := &skipNode[K, V]{
+		key:   key,
+		value: value,
+		next:  make([]*skipNode[K, V], lvl),
+		span:  make([]int, lvl),
+	}
+	for i := 0; i < lvl; i++ {
+		n.next[i] = update[i].next[i]
+		update[i].next[i] = n
+		n.span[i] = update[i].span[i] - (rank[0] - rank[i])
+		update[i].span[i] = rank[0] - rank[i] + 1
+	}
+	for i := lvl; i < s.level; i++ {
+		update[i].span[i]++
+	}
+	s.length++
+}
+
+func (s *SortedMap[K, V]) Load(key K) (V, bool) {
+	if n := s.ceilingNode(key); n != nil && cmp.Compare(n.key, key) == 0 {
+		return n.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+func (s *SortedMap[K, V]) Delete(key K) {
+	update, _ := s.findPath(key)
+	x := update[0].next[0]
+	if x == nil || cmp.Compare(x.key, key) != 0 {
+		return
+	}
+
+	for i := 0; i < s.level; i++ {
+		if update[i].next[i] == x {
+			update[i].span[i] += x.span[i] - 1
+			update[i].next[i] = x.next[i]
+		} else {
+			update[i].span[i]--
+		}
+	}
+	for s.level > 1 && s.head.next[s.level-1] == nil {
+		s.level--
+	}
+	s.length--
+}
+
+func (s *SortedMap[K, V]) Len() int64 {
+	return int64(s.length)
+}
+
+// Range calls f for each entry in ascending key order until f returns false.
+// The map must not be modified during iteration.
+func (s *SortedMap[K, V]) Range(f func(key K, value V) bool) {
+	if f == nil {
+		return
+	}
+
+	for n := s.head.next[0]; n != nil; n = n.next[0] {
+		if !f(n.key, n.value) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over all entries in ascending key order.
+// The map must not be modified during iteration.
+func (s *SortedMap[K, V]) All() iter.Seq2[K, V] {
+	return s.Range
+}
+
+// Between returns an iterator over entries with from <= key < to in
+// ascending key order. The map must not be modified during iteration.
+func (s *SortedMap[K, V]) Between(from, to K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for n := s.ceilingNode(from); n != nil && cmp.Less(n.key, to); n = n.next[0] {
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Min returns the entry with the smallest key.
+// The boolean result is false if the map is empty.
+func (s *SortedMap[K, V]) Min() (K, V, bool) {
+	return skipEntry(s.head.next[0])
+}
+
+// Max returns the entry with the largest key.
+// The boolean result is false if the map is empty.
+func (s *SortedMap[K, V]) Max() (K, V, bool) {
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil {
+			x = x.next[i]
+		}
+	}
+	if x == s.head {
+		return skipEntry[K, V](nil)
+	}
+	return skipEntry(x)
+}
+
+// Floor returns the entry with the largest key less than or equal to key.
+// The boolean result is false if there is no such entry.
+func (s *SortedMap[K, V]) Floor(key K) (K, V, bool) {
+	update, _ := s.findPath(key)
+	if n := update[0].next[0]; n != nil && cmp.Compare(n.key, key) == 0 {
+		return skipEntry(n)
+	}
+	if update[0] == s.head {
+		return skipEntry[K, V](nil)
+	}
+	return skipEntry(update[0])
+}
+
+// Ceiling returns the entry with the smallest key greater than or equal to key.
+// The boolean result is false if there is no such entry.
+func (s *SortedMap[K, V]) Ceiling(key K) (K, V, bool) {
+	return skipEntry(s.ceilingNode(key))
+}
+
+// Rank returns the number of keys strictly less than key.
+func (s *SortedMap[K, V]) Rank(key K) int {
+	_, rank := s.findPath(key)
+	return rank[0]
+}
+
+// Select returns the entry at zero-based position i in key order.
+// The boolean result is false if i is out of range.
+func (s *SortedMap[K, V]) Select(i int) (K, V, bool) {
+	if i < 0 || i >= s.length {
+		return skipEntry[K, V](nil)
+	}
+
+	target := i + 1
+	traversed := 0
+	x := s.head
+	for lvl := s.level - 1; lvl >= 0; lvl-- {
+		for x.next[lvl] != nil && traversed+x.span[lvl] <= target {
+			traversed += x.span[lvl]
+			x = x.next[lvl]
+		}
+		if traversed == target {
+			return skipEntry(x)
+		}
+	}
+	return skipEntry[K, V](nil)
+}
+
+func (s *SortedMap[K, V]) ceilingNode(key K) *skipNode[K, V] {
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && cmp.Less(x.next[i].key, key) {
+			x = x.next[i]
+		}
+	}
+	return x.next[0]
+}
+
+func skipEntry[K cmp.Ordered, V any](n *skipNode[K, V]) (K, V, bool) {
+	if n == nil {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+	return n.key, n.value, true
+}
+
+func randomSkipLevel() int {
+	lvl := 1
+	for lvl < skipListMaxLevel && rand.IntN(skipListP) == 0 {
+		lvl++
+	}
+	return lvl
+}
+
+// SyncSortedMap is a SortedMap guarded by a sync.RWMutex.
+// Safe for concurrent use.
+type SyncSortedMap[K cmp.Ordered, V any] struct {
+	mu sync.RWMutex
+	m  *SortedMap[K, V]
+}
+
+// NewSyncSortedMap returns a new empty SyncSortedMap.
+func NewSyncSortedMap[K cmp.Ordered, V any]() *SyncSortedMap[K, V] {
+	return &SyncSortedMap[K, V]{
+		m: NewSortedMap[K, V](),
+	}
+}
+
+func (s *SyncSortedMap[K, V]) Store(key K, value V) {
+	s.mu.Lock()
+	s.m.Store(key, value)
+	s.mu.Unlock()
+}
+
+func (s *SyncSortedMap[K, V]) Load(key K) (V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Load(key)
+}
+
+func (s *SyncSortedMap[K, V]) Delete(key K) {
+	s.mu.Lock()
+	s.m.Delete(key)
+	s.mu.Unlock()
+}
+
+func (s *SyncSortedMap[K, V]) Len() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Len()
+}
+
+// Range calls f for each entry in ascending key order until f returns false.
+// It iterates over a copy taken under the lock, so f may modify the map.
+func (s *SyncSortedMap[K, V]) Range(f func(key K, value V) bool) {
+	if f == nil {
+		return
+	}
+	s.All()(f)
+}
+
+// All returns an iterator over a copy of all entries taken when iteration starts.
+func (s *SyncSortedMap[K, V]) All() iter.Seq2[K, V] {
+	return s.collect(s.m.All)
+}
+
+// Between returns an iterator over a copy of the entries with
+// from <= key < to, taken when iteration starts.
+func (s *SyncSortedMap[K, V]) Between(from, to K) iter.Seq2[K, V] {
+	return s.collect(func() iter.Seq2[K, V] {
+		return s.m.Between(from, to)
+	})
+}
+
+func (s *SyncSortedMap[K, V]) collect(seq func() iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		var keys []K
+		var values []V
+		s.mu.RLock()
+		for k, v := range seq() {
+			keys = append(keys, k)
+			values = append(values, v)
+		}
+		s.mu.RUnlock()
+
+		for i := range keys {
+			if !yield(keys[i], values[i]) {
+				return
+			}
+		}
+	}
+}
+
+func (s *SyncSortedMap[K, V]) Min() (K, V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Min()
+}
+
+func (s *SyncSortedMap[K, V]) Max() (K, V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Max()
+}
+
+func (s *SyncSortedMap[K, V]) Floor(key K) (K, V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Floor(key)
+}
+
+func (s *SyncSortedMap[K, V]) Ceiling(key K) (K, V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Ceiling(key)
+}
+
+func (s *SyncSortedMap[K, V]) Rank(key K) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Rank(key)
+}
+
+func (s *SyncSortedMap[K, V]) Select(i int) (K, V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Select(i)
+}
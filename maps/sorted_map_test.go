@@ -0,0 +1,236 @@
+package maps
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestSortedMap_Basic(t *testing.T) {
+	m := NewSortedMap[int, string]()
+	m.Store(5, "five")
+	m.Store(1, "one")
+	m.Store(3, "three")
+	m.Store(3, "THREE")
+
+	if v, ok := m.Load(3); !ok || v != "THREE" {
+		t.Fatalf("expected (\"THREE\", true), got (%v, %v)", v, ok)
+	}
+	if _, ok := m.Load(2); ok {
+		t.Fatal("expected missing key 2")
+	}
+	if m.Len() != 3 {
+		t.Fatalf("expected len 3, got %d", m.Len())
+	}
+
+	var keys []int
+	for k := range m.All() {
+		keys = append(keys, k)
+	}
+	if want := []int{1, 3, 5}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("expected keys %v, got %v", want, keys)
+	}
+
+	m.Delete(3)
+	m.Delete(42)
+	if m.Len() != 2 {
+		t.Fatalf("expected len 2 after delete, got %d", m.Len())
+	}
+	if _, ok := m.Load(3); ok {
+		t.Fatal("expected key 3 to be deleted")
+	}
+}
+
+func TestSortedMap_Empty(t *testing.T) {
+	m := NewSortedMap[int, int]()
+	if _, _, ok := m.Min(); ok {
+		t.Fatal("expected Min on empty map to return false")
+	}
+	if _, _, ok := m.Max(); ok {
+		t.Fatal("expected Max on empty map to return false")
+	}
+	if _, _, ok := m.Floor(1); ok {
+		t.Fatal("expected Floor on empty map to return false")
+	}
+	if _, _, ok := m.Ceiling(1); ok {
+		t.Fatal("expected Ceiling on empty map to return false")
+	}
+	if _, _, ok := m.Select(0); ok {
+		t.Fatal("expected Select on empty map to return false")
+	}
+	if r := m.Rank(1); r != 0 {
+		t.Fatalf("expected rank 0, got %d", r)
+	}
+	m.Range(nil)
+}
+
+func TestSortedMap_Navigation(t *testing.T) {
+	m := NewSortedMap[int, int]()
+	for _, k := range []int{10, 20, 30, 40} {
+		m.Store(k, k*10)
+	}
+
+	cases := []struct {
+		name   string
+		got    func() (int, int, bool)
+		wantK  int
+		wantOk bool
+	}{
+		{"Min", m.Min, 10, true},
+		{"Max", m.Max, 40, true},
+		{"Floor(25)", func() (int, int, bool) { return m.Floor(25) }, 20, true},
+		{"Floor(30)", func() (int, int, bool) { return m.Floor(30) }, 30, true},
+		{"Floor(5)", func() (int, int, bool) { return m.Floor(5) }, 0, false},
+		{"Ceiling(25)", func() (int, int, bool) { return m.Ceiling(25) }, 30, true},
+		{"Ceiling(30)", func() (int, int, bool) { return m.Ceiling(30) }, 30, true},
+		{"Ceiling(45)", func() (int, int, bool) { return m.Ceiling(45) }, 0, false},
+		{"Select(0)", func() (int, int, bool) { return m.Select(0) }, 10, true},
+		{"Select(3)", func() (int, int, bool) { return m.Select(3) }, 40, true},
+		{"Select(4)", func() (int, int, bool) { return m.Select(4) }, 0, false},
+		{"Select(-1)", func() (int, int, bool) { return m.Select(-1) }, 0, false},
+	}
+	for _, c := range cases {
+		k, v, ok := c.got()
+		if ok != c.wantOk || k != c.wantK || (ok && v != k*10) {
+			t.Errorf("%s = (%d, %d, %v), want key %d, ok %v", c.name, k, v, ok, c.wantK, c.wantOk)
+		}
+	}
+
+	for key, want := range map[int]int{5: 0, 10: 0, 15: 1, 40: 3, 45: 4} {
+		if got := m.Rank(key); got != want {
+			t.Errorf("Rank(%d) = %d, want %d", key, got, want)
+		}
+	}
+}
+
+func TestSortedMap_Between(t *testing.T) {
+	m := NewSortedMap[string, int]()
+	for i, k := range []string{"apple", "banana", "cherry", "date", "elder"} {
+		m.Store(k, i)
+	}
+
+	var keys []string
+	for k := range m.Between("b", "d") {
+		keys = append(keys, k)
+	}
+	if want := []string{"banana", "cherry"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("expected %v, got %v", want, keys)
+	}
+
+	keys = keys[:0]
+	for k := range m.Between("banana", "zzz") {
+		keys = append(keys, k)
+		if len(keys) == 2 {
+			break
+		}
+	}
+	if want := []string{"banana", "cherry"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("expected early stop with %v, got %v", want, keys)
+	}
+
+	for range m.Between("x", "a") {
+		t.Fatal("expected empty range when from > to")
+	}
+}
+
+// Compares SortedMap with a sorted slice under random operations.
+func TestSortedMap_RandomizedAgainstReference(t *testing.T) {
+	m := NewSortedMap[int, int]()
+	ref := make(map[int]int)
+	r := rand.New(rand.NewPCG(1, 2))
+
+	for i := 0; i < 5000; i++ {
+		k := r.IntN(500)
+		if r.IntN(3) == 0 {
+			m.Delete(k)
+			delete(ref, k)
+		} else {
+			m.Store(k, i)
+			ref[k] = i
+		}
+	}
+
+	keys := make([]int, 0, len(ref))
+	for k := range ref {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	if m.Len() != int64(len(keys)) {
+		t.Fatalf("expected len %d, got %d", len(keys), m.Len())
+	}
+	i := 0
+	m.Range(func(k, v int) bool {
+		if k != keys[i] || v != ref[k] {
+			t.Fatalf("position %d: expected (%d, %d), got (%d, %d)", i, keys[i], ref[keys[i]], k, v)
+		}
+		i++
+		return true
+	})
+	for i, k := range keys {
+		if got := m.Rank(k); got != i {
+			t.Fatalf("Rank(%d) = %d, want %d", k, got, i)
+		}
+		if sk, _, ok := m.Select(i); !ok || sk != k {
+			t.Fatalf("Select(%d) = (%d, %v), want %d", i, sk, ok, k)
+		}
+	}
+	if k, _, _ := m.Max(); k != keys[len(keys)-1] {
+		t.Fatalf("expected Max %d, got %d", keys[len(keys)-1], k)
+	}
+}
+
+func TestSyncSortedMap(t *testing.T) {
+	m := NewSyncSortedMap[int, int]()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(k int) {
+			defer wg.Done()
+			m.Store(k, k)
+			m.Load(k)
+			m.Floor(k)
+			m.Ceiling(k)
+			m.Rank(k)
+			m.Select(0)
+			m.Min()
+			m.Max()
+			for range m.Between(0, 10) {
+			}
+			if k%2 == 1 {
+				m.Delete(k)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if m.Len() != 25 {
+		t.Fatalf("expected len 25, got %d", m.Len())
+	}
+
+	var keys []int
+	for k := range m.Between(10, 20) {
+		keys = append(keys, k)
+	}
+	if want := []int{10, 12, 14, 16, 18}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("expected %v, got %v", want, keys)
+	}
+
+	seen := 0
+	m.Range(func(k, v int) bool {
+		m.Delete(k) // modifying the map from f must not deadlock
+		seen++
+		return true
+	})
+	if seen != 25 || m.Len() != 0 {
+		t.Fatalf("expected to visit and delete 25 entries, visited %d, len %d", seen, m.Len())
+	}
+	m.Range(nil)
+
+	m.Store(1, 1)
+	m.Store(2, 2)
+	for range m.All() {
+		break
+	}
+}
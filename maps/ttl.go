@@ -0,0 +1,60 @@
+package maps
+
+import (
+	"context"
+	"time"
+)
+
+// The sliding-expiration rules shared by TtlTypedSyncMap and the TTL
+// variant of IndexedMap.
+
+const defaultTTL = time.Second
+
+type ttlEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+func newTTLEntry[V any](value V, now time.Time, ttl time.Duration) ttlEntry[V] {
+	return ttlEntry[V]{value: value, expiresAt: now.Add(ttl)}
+}
+
+func (e ttlEntry[V]) expired(now time.Time) bool {
+	return now.After(e.expiresAt)
+}
+
+// prolonged returns e with its expiration slid to ttl after now.
+func (e ttlEntry[V]) prolonged(now time.Time, ttl time.Duration) ttlEntry[V] {
+	e.expiresAt = now.Add(ttl)
+	return e
+}
+
+// ttlDurations applies the defaults for a TTL and a janitor interval:
+// one second, and half the TTL.
+func ttlDurations(ttl, sanitizeInterval time.Duration) (time.Duration, time.Duration) {
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	if sanitizeInterval <= 0 {
+		sanitizeInterval = ttl / 2
+		if sanitizeInterval <= 0 {
+			sanitizeInterval = defaultTTL / 2
+		}
+	}
+	return ttl, sanitizeInterval
+}
+
+// runJanitor calls sweep every interval until ctx is done.
+func runJanitor(ctx context.Context, interval time.Duration, sweep func(now time.Time)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sweep(time.Now())
+		}
+	}
+}
@@ -3,6 +3,7 @@ package maps
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -14,11 +15,7 @@ type TtlTypedSyncMap[K comparable, V any] struct {
 	expDuration      time.Duration
 	mu               sync.Mutex
 	items            map[K]ttlEntry[V]
-}
-
-type ttlEntry[V any] struct {
-	value     V
-	expiresAt time.Time
+	encodeTTL        atomic.Bool
 }
 
 func NewTtlTypedSyncMap[K comparable, V any](
@@ -26,16 +23,7 @@ func NewTtlTypedSyncMap[K comparable, V any](
 	expDuration time.Duration,
 	sanitizeInterval time.Duration,
 ) *TtlTypedSyncMap[K, V] {
-	if expDuration <= 0 {
-		expDuration = time.Second
-	}
-	if sanitizeInterval <= 0 {
-		sanitizeInterval = expDuration / 2
-		if sanitizeInterval <= 0 {
-			sanitizeInterval = time.Second / 2
-		}
-	}
-
+	expDuration, sanitizeInterval = ttlDurations(expDuration, sanitizeInterval)
 	res := &TtlTypedSyncMap[K, V]{
 		ctx:              ctx,
 		expDuration:      expDuration,
@@ -48,11 +36,7 @@ func NewTtlTypedSyncMap[K comparable, V any](
 
 func (t *TtlTypedSyncMap[K, V]) Store(key K, value V) {
 	t.mu.Lock()
-	now := time.Now()
-	t.items[key] = ttlEntry[V]{
-		value:     value,
-		expiresAt: now.Add(t.expDuration),
-	}
+	t.items[key] = newTTLEntry(value, time.Now(), t.expDuration)
 	t.mu.Unlock()
 }
 
@@ -67,15 +51,14 @@ func (t *TtlTypedSyncMap[K, V]) Load(key K) (V, bool) {
 	}
 
 	now := time.Now()
-	if now.After(entry.expiresAt) {
+	if entry.expired(now) {
 		delete(t.items, key)
 		t.mu.Unlock()
 		return zero, false
 	}
 
 	// sliding TTL
-	entry.expiresAt = now.Add(t.expDuration)
-	t.items[key] = entry
+	t.items[key] = entry.prolonged(now, t.expDuration)
 	v := entry.value
 
 	t.mu.Unlock()
@@ -101,14 +84,38 @@ func (t *TtlTypedSyncMap[K, V]) Range(f func(key K, value V) bool) {
 	defer t.mu.Unlock()
 
 	for k, entry := range t.items {
-		if now.After(entry.expiresAt) {
+		if entry.expired(now) {
+			delete(t.items, k)
+			continue
+		}
+
+		// sliding TTL
+		t.items[k] = entry.prolonged(now, t.expDuration)
+
+		if !f(k, entry.value) {
+			break
+		}
+	}
+}
+
+// rangeMatching is Range restricted to the keys match accepts: only those
+// entries are visited and prolonged.
+func (t *TtlTypedSyncMap[K, V]) rangeMatching(match func(key K) bool, f func(key K, value V) bool) {
+	t.mu.Lock()
+	now := time.Now()
+	defer t.mu.Unlock()
+
+	for k, entry := range t.items {
+		if !match(k) {
+			continue
+		}
+		if entry.expired(now) {
 			delete(t.items, k)
 			continue
 		}
 
 		// sliding TTL
-		entry.expiresAt = now.Add(t.expDuration)
-		t.items[k] = entry
+		t.items[k] = entry.prolonged(now, t.expDuration)
 
 		if !f(k, entry.value) {
 			break
@@ -116,23 +123,58 @@ func (t *TtlTypedSyncMap[K, V]) Range(f func(key K, value V) bool) {
 	}
 }
 
+// countMatching returns the number of live entries whose key match accepts,
+// without prolonging them.
+func (t *TtlTypedSyncMap[K, V]) countMatching(match func(key K) bool) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	var n int64
+	for k, entry := range t.items {
+		if match(k) && !entry.expired(now) {
+			n++
+		}
+	}
+	return n
+}
+
+// deleteMatching removes the entries whose key match accepts and returns
+// how many live entries it removed.
+func (t *TtlTypedSyncMap[K, V]) deleteMatching(match func(key K) bool) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	n := 0
+	for k, entry := range t.items {
+		if !match(k) {
+			continue
+		}
+		if !entry.expired(now) {
+			n++
+		}
+		delete(t.items, k)
+	}
+	return n
+}
+
+// TTL returns the period of inactivity after which an entry expires.
+func (t *TtlTypedSyncMap[K, V]) TTL() time.Duration {
+	return t.expDuration
+}
+
 func (t *TtlTypedSyncMap[K, V]) sanitize() {
-	ticker := time.NewTicker(t.sanitizeInterval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-t.ctx.Done():
-			return
-		case <-ticker.C:
-			t.mu.Lock()
-			now := time.Now()
-			for k, entry := range t.items {
-				if now.After(entry.expiresAt) {
-					delete(t.items, k)
-				}
-			}
-			t.mu.Unlock()
+	runJanitor(t.ctx, t.sanitizeInterval, t.sweep)
+}
+
+func (t *TtlTypedSyncMap[K, V]) sweep(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for k, entry := range t.items {
+		if entry.expired(now) {
+			delete(t.items, k)
 		}
 	}
 }
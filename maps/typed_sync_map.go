@@ -1,13 +1,26 @@
 package maps
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"sync"
 	"sync/atomic"
 )
 
+// maxReportedMismatches limits how many offending entries
+// NewFromSyncMapChecked lists in its error.
+const maxReportedMismatches = 10
+
+// TypedSyncMap is a type-safe sync.Map with an O(1) Len. Every write takes
+// a read lock on one RWMutex shared by the whole map so that Snapshot can
+// exclude writers, which limits write scaling compared to a bare sync.Map.
 type TypedSyncMap[K comparable, V any] struct {
 	m     *sync.Map
 	count atomic.Int64
+	// snapMu is held shared by writers and exclusively by Snapshot,
+	// so that a snapshot observes no partially applied writes.
+	snapMu sync.RWMutex
 }
 
 func NewTypedSyncMap[K comparable, V any]() *TypedSyncMap[K, V] {
@@ -17,6 +30,13 @@ func NewTypedSyncMap[K comparable, V any]() *TypedSyncMap[K, V] {
 	}
 }
 
+// NewFromSyncMap wraps m without checking the types of its entries.
+// Load and Range panic if m holds keys or values that are not K and V;
+// use NewFromSyncMapChecked when the contents of m are not trusted.
+//
+// The returned map takes ownership of m: it must not be mutated
+// directly afterwards. External writes are not reflected in Len and
+// may introduce entries of foreign types.
 func NewFromSyncMap[K comparable, V any](m *sync.Map) *TypedSyncMap[K, V] {
 	t := &TypedSyncMap[K, V]{m: m}
 	var cnt int64
@@ -28,11 +48,65 @@ func NewFromSyncMap[K comparable, V any](m *sync.Map) *TypedSyncMap[K, V] {
 	return t
 }
 
+// NewFromSyncMapChecked is like NewFromSyncMap but validates every key and
+// value while counting entries. If any entry is not of type K and V, it
+// returns an error wrapping ErrTypeMismatch that lists offending entries.
+// The same ownership rules as for NewFromSyncMap apply afterwards.
+func NewFromSyncMapChecked[K comparable, V any](m *sync.Map) (*TypedSyncMap[K, V], error) {
+	var cnt int64
+	var mismatches []string
+	total := 0
+	m.Range(func(k, v any) bool {
+		cnt++
+		_, keyOk := k.(K)
+		_, valOk := v.(V)
+		if keyOk && valOk {
+			return true
+		}
+		total++
+		if len(mismatches) < maxReportedMismatches {
+			mismatches = append(mismatches, describeMismatch[K, V](k, v, keyOk, valOk))
+		}
+		return true
+	})
+	if total > 0 {
+		if total > len(mismatches) {
+			mismatches = append(mismatches, fmt.Sprintf("and %d more", total-len(mismatches)))
+		}
+		return nil, fmt.Errorf("%w: %s", ErrTypeMismatch, strings.Join(mismatches, "; "))
+	}
+
+	t := &TypedSyncMap[K, V]{m: m}
+	t.count.Store(cnt)
+	return t, nil
+}
+
+// NewFromMap returns a TypedSyncMap holding a copy of src.
+func NewFromMap[K comparable, V any](src map[K]V) *TypedSyncMap[K, V] {
+	t := NewTypedSyncMap[K, V]()
+	for k, v := range src {
+		t.m.Store(k, v)
+	}
+	t.count.Store(int64(len(src)))
+	return t
+}
+
+func describeMismatch[K comparable, V any](k, v any, keyOk, valOk bool) string {
+	var parts []string
+	if !keyOk {
+		parts = append(parts, fmt.Sprintf("key has type %T, want %v", k, reflect.TypeFor[K]()))
+	}
+	if !valOk {
+		parts = append(parts, fmt.Sprintf("value has type %T, want %v", v, reflect.TypeFor[V]()))
+	}
+	return fmt.Sprintf("entry %v: %s", k, strings.Join(parts, ", "))
+}
+
 func (t *TypedSyncMap[K, V]) Store(key K, value V) {
-	_, loaded := t.m.LoadOrStore(key, value)
-	if loaded {
-		t.m.Store(key, value)
-	} else {
+	t.snapMu.RLock()
+	defer t.snapMu.RUnlock()
+
+	if _, loaded := t.m.Swap(key, value); !loaded {
 		t.count.Add(1)
 	}
 }
@@ -47,12 +121,65 @@ func (t *TypedSyncMap[K, V]) Load(key K) (V, bool) {
 }
 
 func (t *TypedSyncMap[K, V]) Delete(key K) {
-	if _, existed := t.m.Load(key); existed {
-		t.m.Delete(key)
+	t.snapMu.RLock()
+	defer t.snapMu.RUnlock()
+
+	if _, existed := t.m.LoadAndDelete(key); existed {
 		t.count.Add(-1)
 	}
 }
 
+// Update atomically replaces the value for key with fn(old, ok), where ok
+// reports whether key was present, and returns the stored value.
+//
+// fn may be called several times under contention and must be free of side
+// effects. Values are compared with CompareAndSwap, so the dynamic type of V
+// must be comparable; otherwise Update panics.
+func (t *TypedSyncMap[K, V]) Update(key K, fn func(old V, ok bool) V) V {
+	v, _ := t.Compute(key, func(old V, ok bool) (V, bool) {
+		return fn(old, ok), true
+	})
+	return v
+}
+
+// Compute is like Update, but fn additionally decides whether the entry is
+// kept: if fn returns keep == false, the key is deleted. Compute returns the
+// resulting value and whether the key is present afterwards.
+//
+// The same restrictions on fn and V as for Update apply.
+func (t *TypedSyncMap[K, V]) Compute(key K, fn func(old V, ok bool) (value V, keep bool)) (V, bool) {
+	t.snapMu.RLock()
+	defer t.snapMu.RUnlock()
+
+	var zero V
+	for {
+		old, loaded := t.m.Load(key)
+		if !loaded {
+			value, keep := fn(zero, false)
+			if !keep {
+				return zero, false
+			}
+			if _, loaded = t.m.LoadOrStore(key, value); !loaded {
+				t.count.Add(1)
+				return value, true
+			}
+			continue
+		}
+
+		value, keep := fn(old.(V), true)
+		if !keep {
+			if t.m.CompareAndDelete(key, old) {
+				t.count.Add(-1)
+				return zero, false
+			}
+			continue
+		}
+		if t.m.CompareAndSwap(key, old, value) {
+			return value, true
+		}
+	}
+}
+
 func (t *TypedSyncMap[K, V]) Len() int64 {
 	return t.count.Load()
 }
@@ -68,3 +195,25 @@ func (t *TypedSyncMap[K, V]) Range(f func(key K, value V) bool) {
 		return f(key, val)
 	})
 }
+
+// Snapshot returns a read-only view of the map at a single point in time.
+// Writes are blocked while the snapshot is taken; loads are not.
+func (t *TypedSyncMap[K, V]) Snapshot() Snapshot[K, V] {
+	return Snapshot[K, V]{items: t.ToMap()}
+}
+
+// ToMap returns a copy of the map's contents at a single point in time.
+func (t *TypedSyncMap[K, V]) ToMap() map[K]V {
+	t.snapMu.Lock()
+	defer t.snapMu.Unlock()
+
+	res := make(map[K]V, t.count.Load())
+	if t.m == nil {
+		return res
+	}
+	t.m.Range(func(k, v any) bool {
+		res[k.(K)] = v.(V)
+		return true
+	})
+	return res
+}
@@ -1,7 +1,10 @@
 package maps
 
 import (
+	"errors"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -144,3 +147,254 @@ func TestTypedSyncMap_Range_OnNilMap(t *testing.T) {
 		return true
 	})
 }
+
+func TestNewFromSyncMapChecked(t *testing.T) {
+	sm := &sync.Map{}
+	sm.Store(1, "a")
+	sm.Store(2, "b")
+	m, err := NewFromSyncMapChecked[int, string](sm)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Len() != 2 {
+		t.Fatalf("expected len 2, got %d", m.Len())
+	}
+	if v, ok := m.Load(2); !ok || v != "b" {
+		t.Fatalf("expected Load(2) to return (\"b\", true), got (%v, %v)", v, ok)
+	}
+}
+
+func TestNewFromSyncMapChecked_TypeMismatch(t *testing.T) {
+	sm := &sync.Map{}
+	sm.Store(1, "a")
+	sm.Store("two", "b")
+	sm.Store(3, 3.0)
+	m, err := NewFromSyncMapChecked[int, string](sm)
+	if m != nil {
+		t.Fatalf("expected nil map, got %v", m)
+	}
+	if !errors.Is(err, ErrTypeMismatch) {
+		t.Fatalf("expected ErrTypeMismatch, got %v", err)
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "entry two: key has type string, want int") {
+		t.Fatalf("expected key mismatch in error, got %q", msg)
+	}
+	if !strings.Contains(msg, "entry 3: value has type float64, want string") {
+		t.Fatalf("expected value mismatch in error, got %q", msg)
+	}
+}
+
+func TestNewFromSyncMapChecked_TruncatesReport(t *testing.T) {
+	sm := &sync.Map{}
+	for i := 0; i < maxReportedMismatches+5; i++ {
+		sm.Store(i, i)
+	}
+	_, err := NewFromSyncMapChecked[int, string](sm)
+	if !errors.Is(err, ErrTypeMismatch) {
+		t.Fatalf("expected ErrTypeMismatch, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "and 5 more") {
+		t.Fatalf("expected truncated report, got %q", err.Error())
+	}
+}
+
+func TestTypedSyncMap_Update(t *testing.T) {
+	m := NewTypedSyncMap[string, int]()
+	inc := func(old int, ok bool) int {
+		if !ok {
+			return 1
+		}
+		return old + 1
+	}
+	if got := m.Update("a", inc); got != 1 {
+		t.Fatalf("expected 1 on first Update, got %d", got)
+	}
+	if got := m.Update("a", inc); got != 2 {
+		t.Fatalf("expected 2 on second Update, got %d", got)
+	}
+	if m.Len() != 1 {
+		t.Fatalf("expected len 1, got %d", m.Len())
+	}
+}
+
+func TestTypedSyncMap_Update_Concurrent(t *testing.T) {
+	m := NewTypedSyncMap[int, int]()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m.Update(j%5, func(old int, _ bool) int { return old + 1 })
+			}
+		}()
+	}
+	wg.Wait()
+	for k := 0; k < 5; k++ {
+		if v, _ := m.Load(k); v != 1000 {
+			t.Fatalf("expected counter %d to be 1000, got %d", k, v)
+		}
+	}
+	if m.Len() != 5 {
+		t.Fatalf("expected len 5, got %d", m.Len())
+	}
+}
+
+func TestTypedSyncMap_Compute(t *testing.T) {
+	m := NewTypedSyncMap[string, int]()
+
+	v, ok := m.Compute("a", func(old int, ok bool) (int, bool) { return 0, false })
+	if ok || v != 0 || m.Len() != 0 {
+		t.Fatalf("expected no-op on absent key, got (%d, %v), len %d", v, ok, m.Len())
+	}
+
+	v, ok = m.Compute("a", func(old int, ok bool) (int, bool) { return 5, true })
+	if !ok || v != 5 || m.Len() != 1 {
+		t.Fatalf("expected (5, true) and len 1, got (%d, %v), len %d", v, ok, m.Len())
+	}
+
+	v, ok = m.Compute("a", func(old int, ok bool) (int, bool) {
+		if !ok || old != 5 {
+			t.Fatalf("expected old value (5, true), got (%d, %v)", old, ok)
+		}
+		return 0, false
+	})
+	if ok || v != 0 {
+		t.Fatalf("expected deletion, got (%d, %v)", v, ok)
+	}
+	if _, found := m.Load("a"); found || m.Len() != 0 {
+		t.Fatalf("expected key to be deleted and len 0, got len %d", m.Len())
+	}
+}
+
+func TestTypedSyncMap_Compute_ConcurrentDelete(t *testing.T) {
+	m := NewTypedSyncMap[int, int]()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m.Compute(0, func(old int, ok bool) (int, bool) {
+					if ok {
+						return 0, false
+					}
+					return 1, true
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	_, ok := m.Load(0)
+	if (ok && m.Len() != 1) || (!ok && m.Len() != 0) {
+		t.Fatalf("len %d does not match presence %v", m.Len(), ok)
+	}
+}
+
+func BenchmarkTypedSyncMap_Update_SingleKey(b *testing.B) {
+	m := NewTypedSyncMap[int, int]()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			m.Update(0, func(old int, _ bool) int { return old + 1 })
+		}
+	})
+}
+
+func BenchmarkTypedSyncMap_Update_ManyKeys(b *testing.B) {
+	m := NewTypedSyncMap[int, int]()
+	var seed atomic.Int64
+	b.RunParallel(func(pb *testing.PB) {
+		k := int(seed.Add(1))
+		for pb.Next() {
+			k = (k*31 + 7) % 1024
+			m.Update(k, func(old int, _ bool) int { return old + 1 })
+		}
+	})
+}
+
+func TestNewFromMap(t *testing.T) {
+	src := map[int]string{1: "a", 2: "b"}
+	m := NewFromMap(src)
+	src[3] = "c"
+	if m.Len() != 2 {
+		t.Fatalf("expected len 2, got %d", m.Len())
+	}
+	if v, ok := m.Load(2); !ok || v != "b" {
+		t.Fatalf("expected Load(2) to return (\"b\", true), got (%v, %v)", v, ok)
+	}
+	if _, ok := m.Load(3); ok {
+		t.Fatal("expected NewFromMap to copy src")
+	}
+}
+
+func TestTypedSyncMap_ToMap(t *testing.T) {
+	m := NewTypedSyncMap[int, string]()
+	m.Store(1, "a")
+	m.Store(2, "b")
+	got := m.ToMap()
+	if len(got) != 2 || got[1] != "a" || got[2] != "b" {
+		t.Fatalf("expected {1:\"a\",2:\"b\"}, got %v", got)
+	}
+	got[3] = "c"
+	if m.Len() != 2 {
+		t.Fatalf("expected ToMap to return a copy, len is %d", m.Len())
+	}
+}
+
+func TestTypedSyncMap_Snapshot(t *testing.T) {
+	m := NewTypedSyncMap[int, string]()
+	m.Store(1, "a")
+	snap := m.Snapshot()
+	m.Store(1, "b")
+	m.Store(2, "c")
+	if snap.Len() != 1 {
+		t.Fatalf("expected snapshot len 1, got %d", snap.Len())
+	}
+	if v, ok := snap.Load(1); !ok || v != "a" {
+		t.Fatalf("expected snapshot to keep (\"a\", true), got (%v, %v)", v, ok)
+	}
+}
+
+// A writer stores i under "a" and then under "b". At any single point in
+// time a is either equal to b or one ahead, which a torn view may violate.
+func TestTypedSyncMap_Snapshot_Consistent(t *testing.T) {
+	m := NewTypedSyncMap[string, int]()
+	m.Store("a", 0)
+	m.Store("b", 0)
+
+	done := make(chan struct{})
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			m.Store("a", i)
+			m.Store("b", i)
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		snap := m.Snapshot()
+		a, _ := snap.Load("a")
+		b, _ := snap.Load("b")
+		if a != b && a != b+1 {
+			t.Fatalf("torn snapshot: a=%d, b=%d", a, b)
+		}
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestTypedSyncMap_ToMap_OnNilMap(t *testing.T) {
+	m := NewTypedSyncMap[int, string]()
+	m.m = nil
+	if got := m.ToMap(); len(got) != 0 {
+		t.Fatalf("expected empty map, got %v", got)
+	}
+}
@@ -0,0 +1,257 @@
+package maps
+
+import "sync"
+
+// VersionedMap is a multi-version (MVCC) map. Every write bumps the map's
+// version, and readers can open a Snapshot that keeps seeing the map as of
+// that version while writers continue. Safe for concurrent use.
+//
+// Old versions of an entry are kept only while an open snapshot may still
+// read them; they are garbage-collected on subsequent writes to the key and
+// when the oldest open snapshot is closed. Collection visits only the keys
+// holding old versions or tombstones, not the whole map.
+type VersionedMap[K comparable, V any] struct {
+	mu      sync.RWMutex
+	version uint64
+	// items holds each key's versions in ascending order.
+	items map[K][]versionedValue[V]
+	// prunable holds the keys prune may shrink: those with several
+	// versions or a tombstone.
+	prunable map[K]struct{}
+	live     int64
+	// snapshots counts open snapshots per version.
+	snapshots map[uint64]int
+}
+
+type versionedValue[V any] struct {
+	version uint64
+	value   V
+	deleted bool
+}
+
+// NewVersionedMap returns a new empty VersionedMap at version 0.
+func NewVersionedMap[K comparable, V any]() *VersionedMap[K, V] {
+	return &VersionedMap[K, V]{
+		items:     make(map[K][]versionedValue[V]),
+		prunable:  make(map[K]struct{}),
+		snapshots: make(map[uint64]int),
+	}
+}
+
+func (m *VersionedMap[K, V]) Store(key K, value V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.version++
+	m.put(key, versionedValue[V]{version: m.version, value: value})
+}
+
+func (m *VersionedMap[K, V]) Load(key K) (V, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.loadAt(key, m.version)
+}
+
+// Delete removes key. Deleting an absent key is not a write and does not
+// bump the version.
+func (m *VersionedMap[K, V]) Delete(key K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.loadAt(key, m.version); !ok {
+		return
+	}
+	m.version++
+	m.put(key, versionedValue[V]{version: m.version, deleted: true})
+}
+
+func (m *VersionedMap[K, V]) Len() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.live
+}
+
+// Range calls f for each entry of the latest version until f returns false.
+// It iterates over a copy taken under the lock, so f may modify the map.
+func (m *VersionedMap[K, V]) Range(f func(key K, value V) bool) {
+	if f == nil {
+		return
+	}
+
+	m.mu.RLock()
+	keys, values := m.collectAt(m.version)
+	m.mu.RUnlock()
+	rangeCollected(keys, values, f)
+}
+
+// Version returns the version of the latest write.
+func (m *VersionedMap[K, V]) Version() uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.version
+}
+
+// Snapshot opens a read-only view of the map at its current version.
+// The snapshot must be closed to let old versions be garbage-collected.
+func (m *VersionedMap[K, V]) Snapshot() *VersionedSnapshot[K, V] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.snapshots[m.version]++
+	return &VersionedSnapshot[K, V]{
+		m:       m,
+		version: m.version,
+		len:     m.live,
+	}
+}
+
+// put appends v to key's versions, keeps the live count up to date and
+// drops versions no snapshot can read anymore. Callers must hold m.mu.
+func (m *VersionedMap[K, V]) put(key K, v versionedValue[V]) {
+	_, existed := m.loadAt(key, m.version-1)
+	switch {
+	case !existed && !v.deleted:
+		m.live++
+	case existed && v.deleted:
+		m.live--
+	}
+	m.items[key] = append(m.items[key], v)
+	m.prune(key, m.oldestReadable())
+}
+
+func (m *VersionedMap[K, V]) loadAt(key K, version uint64) (V, bool) {
+	chain := m.items[key]
+	for i := len(chain) - 1; i >= 0; i-- {
+		if chain[i].version <= version {
+			if chain[i].deleted {
+				break
+			}
+			return chain[i].value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// collectAt returns the entries visible at version. Callers must hold m.mu.
+func (m *VersionedMap[K, V]) collectAt(version uint64) ([]K, []V) {
+	keys := make([]K, 0, len(m.items))
+	values := make([]V, 0, len(m.items))
+	for k := range m.items {
+		if v, ok := m.loadAt(k, version); ok {
+			keys = append(keys, k)
+			values = append(values, v)
+		}
+	}
+	return keys, values
+}
+
+func rangeCollected[K comparable, V any](keys []K, values []V, f func(key K, value V) bool) {
+	for i := range keys {
+		if !f(keys[i], values[i]) {
+			return
+		}
+	}
+}
+
+// oldestReadable returns the oldest version an open snapshot or a reader of
+// the latest version can observe. Callers must hold m.mu.
+func (m *VersionedMap[K, V]) oldestReadable() uint64 {
+	oldest := m.version
+	for v := range m.snapshots {
+		oldest = min(oldest, v)
+	}
+	return oldest
+}
+
+// prune drops the versions of key that are shadowed at version oldest,
+// and the key itself once only a tombstone remains. Callers must hold m.mu.
+func (m *VersionedMap[K, V]) prune(key K, oldest uint64) {
+	chain := m.items[key]
+	keep := 0
+	for i := len(chain) - 1; i >= 0; i-- {
+		if chain[i].version <= oldest {
+			keep = i
+			break
+		}
+	}
+	if keep > 0 {
+		clear(chain[:keep])
+		chain = chain[keep:]
+	}
+	if len(chain) == 1 && chain[0].deleted && chain[0].version <= oldest {
+		delete(m.items, key)
+		delete(m.prunable, key)
+		return
+	}
+	m.items[key] = chain
+	if len(chain) > 1 || chain[0].deleted {
+		m.prunable[key] = struct{}{}
+	} else {
+		delete(m.prunable, key)
+	}
+}
+
+func (m *VersionedMap[K, V]) closeSnapshot(version uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	before := m.oldestReadable()
+	m.snapshots[version]--
+	if m.snapshots[version] > 0 {
+		return
+	}
+	delete(m.snapshots, version)
+	oldest := m.oldestReadable()
+	if oldest == before {
+		// an older snapshot still pins every version this one could read
+		return
+	}
+	for k := range m.prunable {
+		m.prune(k, oldest)
+	}
+}
+
+// VersionedSnapshot is a stable read-only view of a VersionedMap at one
+// version. Safe for concurrent use. It must not be used after Close.
+type VersionedSnapshot[K comparable, V any] struct {
+	m         *VersionedMap[K, V]
+	version   uint64
+	len       int64
+	closeOnce sync.Once
+}
+
+func (s *VersionedSnapshot[K, V]) Load(key K) (V, bool) {
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+	return s.m.loadAt(key, s.version)
+}
+
+func (s *VersionedSnapshot[K, V]) Len() int64 {
+	return s.len
+}
+
+// Range calls f for each entry visible at the snapshot's version until f
+// returns false. f may modify the underlying map.
+func (s *VersionedSnapshot[K, V]) Range(f func(key K, value V) bool) {
+	if f == nil {
+		return
+	}
+
+	s.m.mu.RLock()
+	keys, values := s.m.collectAt(s.version)
+	s.m.mu.RUnlock()
+	rangeCollected(keys, values, f)
+}
+
+// Version returns the version the snapshot reads at.
+func (s *VersionedSnapshot[K, V]) Version() uint64 {
+	return s.version
+}
+
+// Close releases the snapshot. Calling Close more than once has no effect.
+func (s *VersionedSnapshot[K, V]) Close() {
+	s.closeOnce.Do(func() {
+		s.m.closeSnapshot(s.version)
+	})
+}
@@ -0,0 +1,226 @@
+package maps
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestVersionedMap_Basic(t *testing.T) {
+	m := NewVersionedMap[string, int]()
+	if m.Version() != 0 {
+		t.Fatalf("expected version 0, got %d", m.Version())
+	}
+	m.Store("a", 1)
+	m.Store("b", 2)
+	m.Store("a", 3)
+	if v, ok := m.Load("a"); !ok || v != 3 {
+		t.Fatalf("expected (3, true), got (%v, %v)", v, ok)
+	}
+	if m.Version() != 3 || m.Len() != 2 {
+		t.Fatalf("expected version 3 and len 2, got %d and %d", m.Version(), m.Len())
+	}
+
+	m.Delete("a")
+	m.Delete("a") // absent key: not a write
+	if m.Version() != 4 || m.Len() != 1 {
+		t.Fatalf("expected version 4 and len 1, got %d and %d", m.Version(), m.Len())
+	}
+	if _, ok := m.Load("a"); ok {
+		t.Fatal("expected key to be deleted")
+	}
+	m.Store("a", 5)
+	if m.Len() != 2 {
+		t.Fatalf("expected re-stored key to count, got len %d", m.Len())
+	}
+}
+
+func TestVersionedMap_SnapshotIsStable(t *testing.T) {
+	m := NewVersionedMap[string, int]()
+	m.Store("a", 1)
+	m.Store("b", 2)
+
+	snap := m.Snapshot()
+	defer snap.Close()
+
+	m.Store("a", 10)
+	m.Delete("b")
+	m.Store("c", 3)
+
+	if snap.Version() != 2 {
+		t.Fatalf("expected snapshot version 2, got %d", snap.Version())
+	}
+	if v, ok := snap.Load("a"); !ok || v != 1 {
+		t.Fatalf("expected snapshot to read (1, true), got (%v, %v)", v, ok)
+	}
+	if v, ok := snap.Load("b"); !ok || v != 2 {
+		t.Fatalf("expected deleted key to stay visible in snapshot, got (%v, %v)", v, ok)
+	}
+	if _, ok := snap.Load("c"); ok {
+		t.Fatal("expected later key to be invisible in snapshot")
+	}
+	if snap.Len() != 2 {
+		t.Fatalf("expected snapshot len 2, got %d", snap.Len())
+	}
+
+	collected := make(map[string]int)
+	snap.Range(func(k string, v int) bool {
+		collected[k] = v
+		return true
+	})
+	if len(collected) != 2 || collected["a"] != 1 || collected["b"] != 2 {
+		t.Fatalf("unexpected snapshot contents %v", collected)
+	}
+
+	latest := make(map[string]int)
+	m.Range(func(k string, v int) bool {
+		latest[k] = v
+		return true
+	})
+	if len(latest) != 2 || latest["a"] != 10 || latest["c"] != 3 {
+		t.Fatalf("unexpected latest contents %v", latest)
+	}
+}
+
+func TestVersionedMap_GarbageCollectsOldVersions(t *testing.T) {
+	m := NewVersionedMap[string, int]()
+	m.Store("a", 1)
+	m.Store("gone", 1)
+
+	snap := m.Snapshot()
+	for i := 2; i <= 10; i++ {
+		m.Store("a", i)
+	}
+	m.Delete("gone")
+
+	if n := len(m.items["a"]); n != 10 {
+		t.Fatalf("expected all versions retained while snapshot is open, got %d", n)
+	}
+
+	snap.Close()
+	snap.Close() // idempotent
+	if n := len(m.items["a"]); n != 1 {
+		t.Fatalf("expected only the latest version after close, got %d", n)
+	}
+	if _, ok := m.items["gone"]; ok {
+		t.Fatal("expected tombstoned key to be collected")
+	}
+	if len(m.snapshots) != 0 {
+		t.Fatalf("expected no open snapshots, got %v", m.snapshots)
+	}
+	if len(m.prunable) != 0 {
+		t.Fatalf("expected nothing left to prune, got %v", m.prunable)
+	}
+
+	// without snapshots, writes collect old versions immediately
+	m.Store("a", 11)
+	if n := len(m.items["a"]); n != 1 {
+		t.Fatalf("expected a single version, got %d", n)
+	}
+}
+
+func TestVersionedMap_SharedSnapshotVersion(t *testing.T) {
+	m := NewVersionedMap[int, int]()
+	m.Store(1, 1)
+	s1 := m.Snapshot()
+	s2 := m.Snapshot()
+	m.Store(1, 2)
+
+	s1.Close()
+	if v, ok := s2.Load(1); !ok || v != 1 {
+		t.Fatalf("expected second snapshot to keep its view, got (%v, %v)", v, ok)
+	}
+	s2.Close()
+}
+
+func TestVersionedMap_CloseNewerSnapshotKeepsOlderView(t *testing.T) {
+	m := NewVersionedMap[int, int]()
+	m.Store(1, 1)
+	older := m.Snapshot()
+	m.Store(1, 2)
+	newer := m.Snapshot()
+	m.Store(1, 3)
+
+	newer.Close()
+	if v, ok := older.Load(1); !ok || v != 1 {
+		t.Fatalf("expected older snapshot to keep its view, got (%v, %v)", v, ok)
+	}
+	older.Close()
+	if n := len(m.items[1]); n != 1 {
+		t.Fatalf("expected a single version after closing all snapshots, got %d", n)
+	}
+}
+
+func TestVersionedMap_RangeStopAndNil(t *testing.T) {
+	m := NewVersionedMap[int, int]()
+	m.Store(1, 1)
+	m.Store(2, 2)
+	snap := m.Snapshot()
+	defer snap.Close()
+
+	for _, r := range []func(func(int, int) bool){m.Range, snap.Range} {
+		times := 0
+		r(func(k, v int) bool {
+			m.Store(k+10, v) // modifying the map from f must not deadlock
+			times++
+			return false
+		})
+		if times != 1 {
+			t.Fatalf("expected Range to stop after first iteration, got %d iterations", times)
+		}
+		r(nil)
+	}
+}
+
+// A writer stores i under "a" and then under "b"; a snapshot read after
+// further writes must still see a == b or a == b+1.
+func TestVersionedMap_ConcurrentReadersSeeConsistentViews(t *testing.T) {
+	m := NewVersionedMap[string, int]()
+	m.Store("a", 0)
+	m.Store("b", 0)
+
+	done := make(chan struct{})
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			m.Store("a", i)
+			m.Store("b", i)
+		}
+	}()
+
+	for i := 0; i < 500; i++ {
+		snap := m.Snapshot()
+		a, _ := snap.Load("a")
+		b, _ := snap.Load("b")
+		if a != b && a != b+1 {
+			t.Fatalf("inconsistent snapshot: a=%d, b=%d", a, b)
+		}
+		snap.Close()
+	}
+	close(done)
+	wg.Wait()
+}
+
+func BenchmarkVersionedMap_UpdateLargeMap(b *testing.B) {
+	m := NewVersionedMap[int, int]()
+	for i := 0; i < 100_000; i++ {
+		m.Store(i, 100)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		from, to := i%100_000, (i+1)%100_000
+		m.Update(func(tx *Txn[int, int]) error {
+			a, _ := tx.Get(from)
+			c, _ := tx.Get(to)
+			tx.Set(from, a-1)
+			tx.Set(to, c+1)
+			return nil
+		})
+	}
+}
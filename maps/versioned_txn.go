@@ -0,0 +1,102 @@
+package maps
+
+// Txn is a transaction over a VersionedMap, passed to VersionedMap.Update.
+// It reads from a snapshot taken when the transaction started and buffers
+// writes until commit. A Txn must not be used after Update returns or from
+// several goroutines.
+type Txn[K comparable, V any] struct {
+	snap   *VersionedSnapshot[K, V]
+	reads  map[K]struct{}
+	writes map[K]txnWrite[V]
+}
+
+type txnWrite[V any] struct {
+	value   V
+	deleted bool
+}
+
+// Get returns the value for key as seen by the transaction, including its
+// own uncommitted writes.
+func (tx *Txn[K, V]) Get(key K) (V, bool) {
+	if w, ok := tx.writes[key]; ok {
+		if w.deleted {
+			var zero V
+			return zero, false
+		}
+		return w.value, true
+	}
+	tx.reads[key] = struct{}{}
+	return tx.snap.Load(key)
+}
+
+// Set buffers a store of value under key.
+func (tx *Txn[K, V]) Set(key K, value V) {
+	tx.writes[key] = txnWrite[V]{value: value}
+}
+
+// Delete buffers a deletion of key.
+func (tx *Txn[K, V]) Delete(key K) {
+	tx.writes[key] = txnWrite[V]{deleted: true}
+}
+
+// Update runs fn in a transaction. If fn returns an error, the transaction
+// is rolled back and the error is returned. Otherwise its writes are
+// committed atomically under a single new version, so no reader observes
+// part of them.
+//
+// Concurrency control is optimistic and never blocks other writers: if a
+// key the transaction read or wrote was changed after the transaction
+// started, nothing is applied and Update returns ErrTxnConflict; callers
+// may retry.
+func (m *VersionedMap[K, V]) Update(fn func(tx *Txn[K, V]) error) error {
+	tx := &Txn[K, V]{
+		snap:   m.Snapshot(),
+		reads:  make(map[K]struct{}),
+		writes: make(map[K]txnWrite[V]),
+	}
+	defer tx.snap.Close()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return m.commit(tx)
+}
+
+func (m *VersionedMap[K, V]) commit(tx *Txn[K, V]) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for k := range tx.reads {
+		if m.changedSince(k, tx.snap.version) {
+			return ErrTxnConflict
+		}
+	}
+	for k := range tx.writes {
+		if m.changedSince(k, tx.snap.version) {
+			return ErrTxnConflict
+		}
+	}
+	// deleting an absent key is not a write
+	for k, w := range tx.writes {
+		if _, ok := m.loadAt(k, m.version); w.deleted && !ok {
+			delete(tx.writes, k)
+		}
+	}
+	if len(tx.writes) == 0 {
+		return nil
+	}
+
+	m.version++
+	for k, w := range tx.writes {
+		m.put(k, versionedValue[V]{version: m.version, value: w.value, deleted: w.deleted})
+	}
+	return nil
+}
+
+// changedSince reports whether key was written after version. The open
+// snapshot of the transaction keeps those writes from being pruned.
+// Callers must hold m.mu.
+func (m *VersionedMap[K, V]) changedSince(key K, version uint64) bool {
+	chain := m.items[key]
+	return len(chain) > 0 && chain[len(chain)-1].version > version
+}
@@ -0,0 +1,172 @@
+package maps
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func transfer(m *VersionedMap[string, int], from, to string, amount int) error {
+	return m.Update(func(tx *Txn[string, int]) error {
+		a, _ := tx.Get(from)
+		b, _ := tx.Get(to)
+		tx.Set(from, a-amount)
+		tx.Set(to, b+amount)
+		return nil
+	})
+}
+
+func TestVersionedMap_Update_Commit(t *testing.T) {
+	m := NewVersionedMap[string, int]()
+	m.Store("a", 100)
+	m.Store("b", 0)
+
+	if err := transfer(m, "a", "b", 30); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	a, _ := m.Load("a")
+	b, _ := m.Load("b")
+	if a != 70 || b != 30 {
+		t.Fatalf("expected a=70, b=30, got a=%d, b=%d", a, b)
+	}
+	if m.Version() != 3 {
+		t.Fatalf("expected all writes under a single version 3, got %d", m.Version())
+	}
+}
+
+func TestVersionedMap_Update_ReadsOwnWrites(t *testing.T) {
+	m := NewVersionedMap[string, int]()
+	m.Store("a", 1)
+	err := m.Update(func(tx *Txn[string, int]) error {
+		tx.Set("a", 2)
+		if v, ok := tx.Get("a"); !ok || v != 2 {
+			t.Fatalf("expected own write (2, true), got (%v, %v)", v, ok)
+		}
+		tx.Delete("a")
+		if _, ok := tx.Get("a"); ok {
+			t.Fatal("expected own delete to hide the key")
+		}
+		tx.Delete("missing")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := m.Load("a"); ok || m.Len() != 0 {
+		t.Fatalf("expected key to be deleted, len %d", m.Len())
+	}
+	if m.Version() != 2 {
+		t.Fatalf("expected a single version for the commit, got %d", m.Version())
+	}
+}
+
+func TestVersionedMap_Update_Rollback(t *testing.T) {
+	m := NewVersionedMap[string, int]()
+	m.Store("a", 1)
+	errAbort := errors.New("abort")
+	err := m.Update(func(tx *Txn[string, int]) error {
+		tx.Set("a", 2)
+		tx.Set("b", 3)
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected errAbort, got %v", err)
+	}
+	if v, _ := m.Load("a"); v != 1 || m.Len() != 1 || m.Version() != 1 {
+		t.Fatalf("expected no changes after rollback, got a=%d, len %d, version %d", v, m.Len(), m.Version())
+	}
+	if len(m.snapshots) != 0 {
+		t.Fatal("expected the transaction snapshot to be released")
+	}
+}
+
+func TestVersionedMap_Update_NoWrites(t *testing.T) {
+	m := NewVersionedMap[string, int]()
+	m.Store("a", 1)
+	err := m.Update(func(tx *Txn[string, int]) error {
+		tx.Get("a")
+		tx.Delete("missing")
+		return nil
+	})
+	if err != nil || m.Version() != 1 {
+		t.Fatalf("expected read-only commit without a new version, got %v, version %d", err, m.Version())
+	}
+}
+
+func TestVersionedMap_Update_Conflict(t *testing.T) {
+	m := NewVersionedMap[string, int]()
+	m.Store("a", 1)
+
+	err := m.Update(func(tx *Txn[string, int]) error {
+		v, _ := tx.Get("a")
+		m.Store("a", 100) // concurrent write after the transaction read a
+		tx.Set("b", v)
+		return nil
+	})
+	if !errors.Is(err, ErrTxnConflict) {
+		t.Fatalf("expected ErrTxnConflict, got %v", err)
+	}
+	if _, ok := m.Load("b"); ok {
+		t.Fatal("expected no writes from the conflicting transaction")
+	}
+
+	// blind writes conflict as well
+	err = m.Update(func(tx *Txn[string, int]) error {
+		m.Delete("a")
+		tx.Set("a", 5)
+		return nil
+	})
+	if !errors.Is(err, ErrTxnConflict) {
+		t.Fatalf("expected ErrTxnConflict for write-write conflict, got %v", err)
+	}
+}
+
+// Concurrent transfers with retries must preserve the total, and no
+// snapshot may observe a partially applied transfer.
+func TestVersionedMap_Update_ConcurrentTransfers(t *testing.T) {
+	m := NewVersionedMap[string, int]()
+	keys := []string{"a", "b", "c"}
+	for _, k := range keys {
+		m.Store(k, 100)
+	}
+
+	wg := sync.WaitGroup{}
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				from, to := keys[(w+i)%3], keys[(w+i+1)%3]
+				for errors.Is(transfer(m, from, to, 1), ErrTxnConflict) {
+				}
+			}
+		}(w)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			snap := m.Snapshot()
+			sum := 0
+			snap.Range(func(_ string, v int) bool {
+				sum += v
+				return true
+			})
+			snap.Close()
+			if sum != 300 {
+				t.Errorf("snapshot observed partial transfer: sum %d", sum)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+
+	sum := 0
+	m.Range(func(_ string, v int) bool {
+		sum += v
+		return true
+	})
+	if sum != 300 {
+		t.Fatalf("expected total 300, got %d", sum)
+	}
+}
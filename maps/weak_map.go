@@ -0,0 +1,233 @@
+package maps
+
+import (
+	"runtime"
+	"sync"
+	"weak"
+)
+
+// WeakValueMap is a concurrent map that holds its values weakly: an entry
+// is removed automatically once its value has been garbage-collected.
+// Safe for concurrent use.
+//
+// Removal runs in a runtime cleanup shortly after the value is collected;
+// until then the entry is kept but Load, Range and Len ignore it.
+type WeakValueMap[K comparable, V any] struct {
+	mu    sync.Mutex
+	items map[K]weakValueEntry[V]
+}
+
+type weakValueEntry[V any] struct {
+	ptr     weak.Pointer[V]
+	cleanup runtime.Cleanup
+}
+
+type weakValueRef[K comparable, V any] struct {
+	key K
+	ptr weak.Pointer[V]
+}
+
+// NewWeakValueMap returns a new empty WeakValueMap.
+func NewWeakValueMap[K comparable, V any]() *WeakValueMap[K, V] {
+	return &WeakValueMap[K, V]{
+		items: make(map[K]weakValueEntry[V]),
+	}
+}
+
+// Store maps key to value without keeping value alive.
+// key must not reference value, or value is never collected.
+// Storing a nil value deletes key.
+func (w *WeakValueMap[K, V]) Store(key K, value *V) {
+	if value == nil {
+		w.Delete(key)
+		return
+	}
+	ptr := weak.Make(value)
+	cleanup := runtime.AddCleanup(value, w.evict, weakValueRef[K, V]{key: key, ptr: ptr})
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if old, ok := w.items[key]; ok {
+		old.cleanup.Stop()
+	}
+	w.items[key] = weakValueEntry[V]{ptr: ptr, cleanup: cleanup}
+}
+
+// Load returns the value for key if it is still alive.
+func (w *WeakValueMap[K, V]) Load(key K) (*V, bool) {
+	w.mu.Lock()
+	entry, ok := w.items[key]
+	w.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	v := entry.ptr.Value()
+	return v, v != nil
+}
+
+func (w *WeakValueMap[K, V]) Delete(key K) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if entry, ok := w.items[key]; ok {
+		entry.cleanup.Stop()
+		delete(w.items, key)
+	}
+}
+
+// Len returns the number of entries whose value is still alive.
+// It checks every entry, so it is O(n).
+func (w *WeakValueMap[K, V]) Len() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var n int64
+	for _, entry := range w.items {
+		if entry.ptr.Value() != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Range calls f for each entry with a live value until f returns false.
+// Values are resolved under the lock before f is called, so f may modify the map.
+func (w *WeakValueMap[K, V]) Range(f func(key K, value *V) bool) {
+	if f == nil {
+		return
+	}
+
+	w.mu.Lock()
+	keys := make([]K, 0, len(w.items))
+	values := make([]*V, 0, len(w.items))
+	for k, entry := range w.items {
+		if v := entry.ptr.Value(); v != nil {
+			keys = append(keys, k)
+			values = append(values, v)
+		}
+	}
+	w.mu.Unlock()
+
+	for i := range keys {
+		if !f(keys[i], values[i]) {
+			return
+		}
+	}
+}
+
+func (w *WeakValueMap[K, V]) evict(ref weakValueRef[K, V]) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// the key may have been re-stored with another value in the meantime
+	if entry, ok := w.items[ref.key]; ok && entry.ptr == ref.ptr {
+		delete(w.items, ref.key)
+	}
+}
+
+// WeakKeyMap is a concurrent map keyed by pointer identity that holds its
+// keys weakly: an entry is removed automatically once its key has been
+// garbage-collected. Safe for concurrent use.
+//
+// Values are held strongly; a value that references its key keeps the
+// entry alive forever. As with WeakValueMap, entries whose key is already
+// gone are ignored until their cleanup removes them.
+type WeakKeyMap[K any, V any] struct {
+	mu    sync.Mutex
+	items map[weak.Pointer[K]]weakKeyEntry[V]
+}
+
+type weakKeyEntry[V any] struct {
+	value   V
+	cleanup runtime.Cleanup
+}
+
+// NewWeakKeyMap returns a new empty WeakKeyMap.
+func NewWeakKeyMap[K any, V any]() *WeakKeyMap[K, V] {
+	return &WeakKeyMap[K, V]{
+		items: make(map[weak.Pointer[K]]weakKeyEntry[V]),
+	}
+}
+
+// Store maps key to value without keeping key alive.
+// A nil key is never stored.
+func (w *WeakKeyMap[K, V]) Store(key *K, value V) {
+	if key == nil {
+		return
+	}
+	ptr := weak.Make(key)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if old, ok := w.items[ptr]; ok {
+		old.value = value
+		w.items[ptr] = old
+		return
+	}
+	w.items[ptr] = weakKeyEntry[V]{
+		value:   value,
+		cleanup: runtime.AddCleanup(key, w.evict, ptr),
+	}
+}
+
+func (w *WeakKeyMap[K, V]) Load(key *K) (V, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	entry, ok := w.items[weak.Make(key)]
+	return entry.value, ok
+}
+
+func (w *WeakKeyMap[K, V]) Delete(key *K) {
+	ptr := weak.Make(key)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if entry, ok := w.items[ptr]; ok {
+		entry.cleanup.Stop()
+		delete(w.items, ptr)
+	}
+}
+
+// Len returns the number of entries whose key is still alive.
+// It checks every entry, so it is O(n).
+func (w *WeakKeyMap[K, V]) Len() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var n int64
+	for ptr := range w.items {
+		if ptr.Value() != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Range calls f for each entry with a live key until f returns false.
+// Keys are resolved under the lock before f is called, so f may modify the map.
+func (w *WeakKeyMap[K, V]) Range(f func(key *K, value V) bool) {
+	if f == nil {
+		return
+	}
+
+	w.mu.Lock()
+	keys := make([]*K, 0, len(w.items))
+	values := make([]V, 0, len(w.items))
+	for ptr, entry := range w.items {
+		if k := ptr.Value(); k != nil {
+			keys = append(keys, k)
+			values = append(values, entry.value)
+		}
+	}
+	w.mu.Unlock()
+
+	for i := range keys {
+		if !f(keys[i], values[i]) {
+			return
+		}
+	}
+}
+
+func (w *WeakKeyMap[K, V]) evict(ptr weak.Pointer[K]) {
+	w.mu.Lock()
+	delete(w.items, ptr)
+	w.mu.Unlock()
+}
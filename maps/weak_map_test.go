@@ -0,0 +1,255 @@
+package maps
+
+import (
+	"runtime"
+	"testing"
+	"time"
+	"weak"
+)
+
+type blob struct {
+	data [64]byte
+	id   int
+}
+
+// waitForLen runs the garbage collector until lenFn returns want or the
+// deadline passes; cleanups run asynchronously after collection.
+func waitForLen(t *testing.T, lenFn func() int64, want int64) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for lenFn() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected len %d, got %d", want, lenFn())
+		}
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWeakValueMap_StoreLoadDelete(t *testing.T) {
+	m := NewWeakValueMap[string, blob]()
+	a := &blob{id: 1}
+	b := &blob{id: 2}
+	m.Store("a", a)
+	m.Store("b", b)
+
+	if v, ok := m.Load("a"); !ok || v != a {
+		t.Fatalf("expected (%p, true), got (%p, %v)", a, v, ok)
+	}
+	if m.Len() != 2 {
+		t.Fatalf("expected len 2, got %d", m.Len())
+	}
+	m.Delete("a")
+	m.Delete("missing")
+	if _, ok := m.Load("a"); ok {
+		t.Fatal("expected key to be deleted")
+	}
+	if _, ok := m.Load("missing"); ok {
+		t.Fatal("expected missing key")
+	}
+	if m.Len() != 1 {
+		t.Fatalf("expected len 1, got %d", m.Len())
+	}
+	runtime.KeepAlive(a)
+	runtime.KeepAlive(b)
+}
+
+func TestWeakValueMap_EvictsCollectedValues(t *testing.T) {
+	m := NewWeakValueMap[int, blob]()
+	kept := &blob{id: 1}
+	m.Store(1, kept)
+	m.Store(2, &blob{id: 2})
+
+	waitForLen(t, m.Len, 1)
+	if v, ok := m.Load(1); !ok || v.id != 1 {
+		t.Fatalf("expected live value to stay, got (%v, %v)", v, ok)
+	}
+	runtime.KeepAlive(kept)
+}
+
+// collectedPointer returns a weak pointer whose value has been collected.
+func collectedPointer(t *testing.T) weak.Pointer[blob] {
+	t.Helper()
+	ptr := weak.Make(&blob{})
+	deadline := time.Now().Add(2 * time.Second)
+	for ptr.Value() != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("expected value to be collected")
+		}
+		runtime.GC()
+	}
+	return ptr
+}
+
+func TestWeakValueMap_LenCountsOnlyLiveValues(t *testing.T) {
+	m := NewWeakValueMap[int, blob]()
+	kept := &blob{id: 1}
+	m.Store(1, kept)
+	// an entry whose cleanup has not run yet
+	m.mu.Lock()
+	m.items[2] = weakValueEntry[blob]{ptr: collectedPointer(t)}
+	m.mu.Unlock()
+
+	if m.Len() != 1 {
+		t.Fatalf("expected len 1, got %d", m.Len())
+	}
+	runtime.KeepAlive(kept)
+}
+
+func TestWeakValueMap_OverwriteKeepsNewValue(t *testing.T) {
+	m := NewWeakValueMap[int, blob]()
+	m.Store(1, &blob{id: 1})
+	replacement := &blob{id: 2}
+	m.Store(1, replacement)
+
+	// the cleanup of the collected first value must not remove the new entry
+	for i := 0; i < 5; i++ {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	if v, ok := m.Load(1); !ok || v.id != 2 {
+		t.Fatalf("expected replacement to stay, got (%v, %v)", v, ok)
+	}
+	runtime.KeepAlive(replacement)
+}
+
+func TestWeakValueMap_StoreNilDeletes(t *testing.T) {
+	m := NewWeakValueMap[string, blob]()
+	a := &blob{id: 1}
+	m.Store("a", a)
+	m.Store("a", nil)
+	m.Store("b", nil)
+	if _, ok := m.Load("a"); ok || m.Len() != 0 {
+		t.Fatalf("expected nil values to delete their keys, got len %d", m.Len())
+	}
+	runtime.KeepAlive(a)
+}
+
+func TestWeakValueMap_Range(t *testing.T) {
+	m := NewWeakValueMap[int, blob]()
+	values := []*blob{{id: 1}, {id: 2}}
+	for i, v := range values {
+		m.Store(i, v)
+	}
+
+	seen := 0
+	m.Range(func(k int, v *blob) bool {
+		if v != values[k] {
+			t.Fatalf("unexpected value for key %d", k)
+		}
+		m.Delete(k) // modifying the map from f must not deadlock
+		seen++
+		return true
+	})
+	if seen != 2 || m.Len() != 0 {
+		t.Fatalf("expected to visit and delete 2 entries, visited %d, len %d", seen, m.Len())
+	}
+
+	for i, v := range values {
+		m.Store(i, v)
+	}
+	times := 0
+	m.Range(func(k int, v *blob) bool {
+		times++
+		return false
+	})
+	if times != 1 {
+		t.Fatalf("expected Range to stop after first iteration, got %d iterations", times)
+	}
+	m.Range(nil)
+	runtime.KeepAlive(values)
+}
+
+func TestWeakKeyMap_StoreLoadDelete(t *testing.T) {
+	m := NewWeakKeyMap[blob, string]()
+	a := &blob{id: 1}
+	b := &blob{id: 1} // equal contents, different identity
+	m.Store(a, "a")
+	m.Store(b, "b")
+	m.Store(a, "a2")
+
+	if v, ok := m.Load(a); !ok || v != "a2" {
+		t.Fatalf("expected (\"a2\", true), got (%v, %v)", v, ok)
+	}
+	if m.Len() != 2 {
+		t.Fatalf("expected len 2, got %d", m.Len())
+	}
+	m.Delete(a)
+	m.Delete(&blob{})
+	if _, ok := m.Load(a); ok {
+		t.Fatal("expected key to be deleted")
+	}
+	if m.Len() != 1 {
+		t.Fatalf("expected len 1, got %d", m.Len())
+	}
+	runtime.KeepAlive(a)
+	runtime.KeepAlive(b)
+}
+
+func TestWeakKeyMap_EvictsCollectedKeys(t *testing.T) {
+	m := NewWeakKeyMap[blob, int]()
+	kept := &blob{id: 1}
+	m.Store(kept, 1)
+	m.Store(&blob{id: 2}, 2)
+
+	waitForLen(t, m.Len, 1)
+	if v, ok := m.Load(kept); !ok || v != 1 {
+		t.Fatalf("expected live key to stay, got (%v, %v)", v, ok)
+	}
+
+	seen := 0
+	m.Range(func(k *blob, v int) bool {
+		if k != kept || v != 1 {
+			t.Fatalf("unexpected entry (%v, %d)", k, v)
+		}
+		seen++
+		return true
+	})
+	if seen != 1 {
+		t.Fatalf("expected 1 entry, got %d", seen)
+	}
+	m.Range(nil)
+	runtime.KeepAlive(kept)
+}
+
+func TestWeakKeyMap_LenCountsOnlyLiveKeys(t *testing.T) {
+	m := NewWeakKeyMap[blob, int]()
+	kept := &blob{id: 1}
+	m.Store(kept, 1)
+	// an entry whose cleanup has not run yet
+	m.mu.Lock()
+	m.items[collectedPointer(t)] = weakKeyEntry[int]{value: 2}
+	m.mu.Unlock()
+
+	if m.Len() != 1 {
+		t.Fatalf("expected len 1, got %d", m.Len())
+	}
+	runtime.KeepAlive(kept)
+}
+
+func TestWeakKeyMap_StoreNilKeyIgnored(t *testing.T) {
+	m := NewWeakKeyMap[blob, int]()
+	m.Store(nil, 1)
+	if _, ok := m.Load(nil); ok || m.Len() != 0 {
+		t.Fatalf("expected nil key not to be stored, got len %d", m.Len())
+	}
+	m.Delete(nil)
+}
+
+func TestWeakKeyMap_Range_Stop(t *testing.T) {
+	m := NewWeakKeyMap[blob, int]()
+	keys := []*blob{{id: 1}, {id: 2}}
+	for i, k := range keys {
+		m.Store(k, i)
+	}
+	times := 0
+	m.Range(func(k *blob, v int) bool {
+		m.Delete(k) // modifying the map from f must not deadlock
+		times++
+		return false
+	})
+	if times != 1 || m.Len() != 1 {
+		t.Fatalf("expected Range to stop after first iteration, got %d iterations, len %d", times, m.Len())
+	}
+	runtime.KeepAlive(keys)
+}
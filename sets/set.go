@@ -0,0 +1,99 @@
+package sets
+
+// Set is a generic set of comparable values.
+// Not safe for concurrent use; see SyncSet.
+type Set[T comparable] struct {
+	items map[T]struct{}
+}
+
+// NewSet returns a new Set containing items.
+func NewSet[T comparable](items ...T) *Set[T] {
+	s := &Set[T]{
+		items: make(map[T]struct{}, len(items)),
+	}
+	for _, item := range items {
+		s.items[item] = struct{}{}
+	}
+	return s
+}
+
+// Add inserts value into the set.
+func (s *Set[T]) Add(value T) {
+	s.items[value] = struct{}{}
+}
+
+// Remove deletes value from the set.
+func (s *Set[T]) Remove(value T) {
+	delete(s.items, value)
+}
+
+// Contains reports whether value is in the set.
+func (s *Set[T]) Contains(value T) bool {
+	_, ok := s.items[value]
+	return ok
+}
+
+// Len returns the number of values in the set.
+func (s *Set[T]) Len() int64 {
+	return int64(len(s.items))
+}
+
+// Range calls f for each value until f returns false.
+func (s *Set[T]) Range(f func(value T) bool) {
+	if f == nil {
+		return
+	}
+
+	for v := range s.items {
+		if !f(v) {
+			return
+		}
+	}
+}
+
+// Union returns a new set with the values that are in s or other.
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	res := NewSet[T]()
+	for v := range s.items {
+		res.Add(v)
+	}
+	for v := range other.items {
+		res.Add(v)
+	}
+	return res
+}
+
+// Intersection returns a new set with the values that are in both s and other.
+func (s *Set[T]) Intersection(other *Set[T]) *Set[T] {
+	res := NewSet[T]()
+	for v := range s.items {
+		if other.Contains(v) {
+			res.Add(v)
+		}
+	}
+	return res
+}
+
+// Difference returns a new set with the values of s that are not in other.
+func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
+	res := NewSet[T]()
+	for v := range s.items {
+		if !other.Contains(v) {
+			res.Add(v)
+		}
+	}
+	return res
+}
+
+// IsSubset reports whether every value of s is in other.
+func (s *Set[T]) IsSubset(other *Set[T]) bool {
+	if s.Len() > other.Len() {
+		return false
+	}
+	for v := range s.items {
+		if !other.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
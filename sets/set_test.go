@@ -0,0 +1,79 @@
+package sets
+
+import (
+	"slices"
+	"testing"
+)
+
+func sorted(r interface{ Range(func(int) bool) }) []int {
+	var res []int
+	r.Range(func(v int) bool {
+		res = append(res, v)
+		return true
+	})
+	slices.Sort(res)
+	return res
+}
+
+func TestSet_AddRemoveContains(t *testing.T) {
+	s := NewSet(1, 2)
+	s.Add(3)
+	s.Add(3)
+	if s.Len() != 3 {
+		t.Fatalf("expected len 3, got %d", s.Len())
+	}
+	if !s.Contains(3) {
+		t.Fatal("expected set to contain 3")
+	}
+	s.Remove(1)
+	s.Remove(42)
+	if s.Contains(1) {
+		t.Fatal("expected 1 to be removed")
+	}
+	if got := sorted(s); !slices.Equal(got, []int{2, 3}) {
+		t.Fatalf("expected [2 3], got %v", got)
+	}
+}
+
+func TestSet_Range_Stop(t *testing.T) {
+	s := NewSet(1, 2, 3)
+	times := 0
+	s.Range(func(v int) bool {
+		times++
+		return false
+	})
+	if times != 1 {
+		t.Fatalf("expected Range to stop after first iteration, got %d iterations", times)
+	}
+	s.Range(nil)
+}
+
+func TestSet_Algebra(t *testing.T) {
+	a := NewSet(1, 2, 3)
+	b := NewSet(2, 3, 4)
+
+	if got := sorted(a.Union(b)); !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Errorf("Union = %v, want [1 2 3 4]", got)
+	}
+	if got := sorted(a.Intersection(b)); !slices.Equal(got, []int{2, 3}) {
+		t.Errorf("Intersection = %v, want [2 3]", got)
+	}
+	if got := sorted(a.Difference(b)); !slices.Equal(got, []int{1}) {
+		t.Errorf("Difference = %v, want [1]", got)
+	}
+	if a.IsSubset(b) {
+		t.Error("expected a not to be a subset of b")
+	}
+	if !NewSet(2, 3).IsSubset(a) {
+		t.Error("expected {2, 3} to be a subset of a")
+	}
+	if NewSet(1, 2, 3, 4).IsSubset(a) {
+		t.Error("expected a larger set not to be a subset")
+	}
+	if !NewSet[int]().IsSubset(a) {
+		t.Error("expected the empty set to be a subset")
+	}
+	if a.Len() != 3 || b.Len() != 3 {
+		t.Error("expected operands to stay unchanged")
+	}
+}
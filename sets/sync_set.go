@@ -0,0 +1,101 @@
+package sets
+
+import "github.com/NLipatov/goutils/maps"
+
+// SyncSet is a concurrent set built on maps.TypedSyncMap.
+// Safe for concurrent use.
+//
+// Set algebra methods iterate over their operands without locking them
+// as a whole, so concurrent writes may or may not be reflected in the result.
+type SyncSet[T comparable] struct {
+	m *maps.TypedSyncMap[T, struct{}]
+}
+
+// NewSyncSet returns a new SyncSet containing items.
+func NewSyncSet[T comparable](items ...T) *SyncSet[T] {
+	s := &SyncSet[T]{
+		m: maps.NewTypedSyncMap[T, struct{}](),
+	}
+	for _, item := range items {
+		s.Add(item)
+	}
+	return s
+}
+
+// Add inserts value into the set.
+func (s *SyncSet[T]) Add(value T) {
+	s.m.Store(value, struct{}{})
+}
+
+// Remove deletes value from the set.
+func (s *SyncSet[T]) Remove(value T) {
+	s.m.Delete(value)
+}
+
+// Contains reports whether value is in the set.
+func (s *SyncSet[T]) Contains(value T) bool {
+	_, ok := s.m.Load(value)
+	return ok
+}
+
+// Len returns the number of values in the set.
+func (s *SyncSet[T]) Len() int64 {
+	return s.m.Len()
+}
+
+// Range calls f for each value until f returns false.
+func (s *SyncSet[T]) Range(f func(value T) bool) {
+	if f == nil {
+		return
+	}
+
+	s.m.Range(func(key T, _ struct{}) bool {
+		return f(key)
+	})
+}
+
+// Union returns a new set with the values that are in s or other.
+func (s *SyncSet[T]) Union(other *SyncSet[T]) *SyncSet[T] {
+	res := NewSyncSet[T]()
+	add := func(v T) bool {
+		res.Add(v)
+		return true
+	}
+	s.Range(add)
+	other.Range(add)
+	return res
+}
+
+// Intersection returns a new set with the values that are in both s and other.
+func (s *SyncSet[T]) Intersection(other *SyncSet[T]) *SyncSet[T] {
+	res := NewSyncSet[T]()
+	s.Range(func(v T) bool {
+		if other.Contains(v) {
+			res.Add(v)
+		}
+		return true
+	})
+	return res
+}
+
+// Difference returns a new set with the values of s that are not in other.
+func (s *SyncSet[T]) Difference(other *SyncSet[T]) *SyncSet[T] {
+	res := NewSyncSet[T]()
+	s.Range(func(v T) bool {
+		if !other.Contains(v) {
+			res.Add(v)
+		}
+		return true
+	})
+	return res
+}
+
+// IsSubset reports whether every value of s is in other.
+func (s *SyncSet[T]) IsSubset(other *SyncSet[T]) bool {
+	subset := true
+	s.Range(func(v T) bool {
+		subset = other.Contains(v)
+		return subset
+	})
+	return subset
+}
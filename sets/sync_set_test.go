@@ -0,0 +1,74 @@
+package sets
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestSyncSet_AddRemoveContains(t *testing.T) {
+	s := NewSyncSet(1, 2)
+	s.Add(3)
+	s.Add(3)
+	if s.Len() != 3 {
+		t.Fatalf("expected len 3, got %d", s.Len())
+	}
+	s.Remove(1)
+	if s.Contains(1) || !s.Contains(2) {
+		t.Fatal("expected set to contain 2 but not 1")
+	}
+	if got := sorted(s); !slices.Equal(got, []int{2, 3}) {
+		t.Fatalf("expected [2 3], got %v", got)
+	}
+
+	times := 0
+	s.Range(func(v int) bool {
+		times++
+		return false
+	})
+	if times != 1 {
+		t.Fatalf("expected Range to stop after first iteration, got %d iterations", times)
+	}
+	s.Range(nil)
+}
+
+func TestSyncSet_Algebra(t *testing.T) {
+	a := NewSyncSet(1, 2, 3)
+	b := NewSyncSet(2, 3, 4)
+
+	if got := sorted(a.Union(b)); !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Errorf("Union = %v, want [1 2 3 4]", got)
+	}
+	if got := sorted(a.Intersection(b)); !slices.Equal(got, []int{2, 3}) {
+		t.Errorf("Intersection = %v, want [2 3]", got)
+	}
+	if got := sorted(a.Difference(b)); !slices.Equal(got, []int{1}) {
+		t.Errorf("Difference = %v, want [1]", got)
+	}
+	if a.IsSubset(b) {
+		t.Error("expected a not to be a subset of b")
+	}
+	if !NewSyncSet(2, 3).IsSubset(a) {
+		t.Error("expected {2, 3} to be a subset of a")
+	}
+}
+
+func TestSyncSet_Concurrent(t *testing.T) {
+	s := NewSyncSet[int]()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(v int) {
+			defer wg.Done()
+			s.Add(v)
+			s.Contains(v)
+			if v%2 == 0 {
+				s.Remove(v)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if s.Len() != 50 {
+		t.Fatalf("expected len 50, got %d", s.Len())
+	}
+}
@@ -0,0 +1,61 @@
+package sets
+
+import (
+	"context"
+	"time"
+
+	"github.com/NLipatov/goutils/maps"
+)
+
+// TtlSet is a concurrent set whose values expire, built on
+// maps.TtlTypedSyncMap. Like the map, it uses sliding expiration:
+// every successful Contains prolongs the value's lifetime.
+type TtlSet[T comparable] struct {
+	m *maps.TtlTypedSyncMap[T, struct{}]
+}
+
+// NewTtlSet returns a new empty TtlSet. The arguments have the same meaning
+// and defaults as for maps.NewTtlTypedSyncMap; the janitor stops when ctx is done.
+func NewTtlSet[T comparable](
+	ctx context.Context,
+	expDuration time.Duration,
+	sanitizeInterval time.Duration,
+) *TtlSet[T] {
+	return &TtlSet[T]{
+		m: maps.NewTtlTypedSyncMap[T, struct{}](ctx, expDuration, sanitizeInterval),
+	}
+}
+
+// Add inserts value into the set or refreshes its expiration.
+func (s *TtlSet[T]) Add(value T) {
+	s.m.Store(value, struct{}{})
+}
+
+// Remove deletes value from the set.
+func (s *TtlSet[T]) Remove(value T) {
+	s.m.Delete(value)
+}
+
+// Contains reports whether value is in the set and has not expired.
+func (s *TtlSet[T]) Contains(value T) bool {
+	_, ok := s.m.Load(value)
+	return ok
+}
+
+// Len returns the number of values in the set, including expired values
+// the janitor has not removed yet.
+func (s *TtlSet[T]) Len() int64 {
+	return s.m.Len()
+}
+
+// Range calls f for each live value until f returns false.
+// f must not call methods of s.
+func (s *TtlSet[T]) Range(f func(value T) bool) {
+	if f == nil {
+		return
+	}
+
+	s.m.Range(func(key T, _ struct{}) bool {
+		return f(key)
+	})
+}
@@ -0,0 +1,35 @@
+package sets
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTtlSet(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := NewTtlSet[int](ctx, 20*time.Millisecond, time.Millisecond)
+
+	s.Add(1)
+	s.Add(2)
+	if !s.Contains(1) || s.Len() != 2 {
+		t.Fatalf("expected set to contain 1 and have len 2, got len %d", s.Len())
+	}
+	s.Remove(2)
+	if s.Contains(2) {
+		t.Fatal("expected 2 to be removed")
+	}
+	if got := sorted(s); len(got) != 1 || got[0] != 1 {
+		t.Fatalf("expected [1], got %v", got)
+	}
+	s.Range(nil)
+
+	time.Sleep(40 * time.Millisecond)
+	if s.Contains(1) {
+		t.Fatal("expected 1 to expire")
+	}
+	if s.Len() != 0 {
+		t.Fatalf("expected len 0 after expiry, got %d", s.Len())
+	}
+}
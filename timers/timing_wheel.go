@@ -0,0 +1,268 @@
+package timers
+
+import (
+	"context"
+	"math/bits"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTick      = time.Millisecond
+	defaultWheelSize = 256
+)
+
+// Clock is the time source of a TimingWheel.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// TimingWheel is a hashed hierarchical timing wheel: a scheduler for large
+// numbers of cheap timeouts. Scheduling and cancelling are O(1), and a
+// timer is moved between wheels at most once per level on its way to expiry.
+// Safe for concurrent use.
+//
+// Time advances in ticks, only when AdvanceTo is called, either directly or
+// by the loop started with Start. A timer fires on the first advance to a
+// time at least its delay after it was scheduled, so it may fire up to one
+// tick late plus the advance period, but never early.
+type TimingWheel struct {
+	mu    sync.Mutex
+	clock Clock
+	tick  time.Duration
+	start time.Time
+	bits  int
+	mask  uint64
+	// next is the next tick to process; every timer due before it has fired.
+	next uint64
+	// levels[l] holds timers due in [size^l, size^(l+1)) ticks from next,
+	// hashed by the l-th group of bits of their expiry tick.
+	levels [][]timerList
+	len    int64
+}
+
+// Timer is a handle to a callback scheduled on a TimingWheel.
+type Timer struct {
+	fn     func()
+	expiry uint64
+	// list is the slot the timer is queued in, nil unless it is pending.
+	list       *timerList
+	prev, next *Timer
+}
+
+// timerList is an intrusive doubly linked list of timers.
+type timerList struct {
+	head, tail *Timer
+}
+
+// NewTimingWheel returns a TimingWheel with the given tick, the time
+// resolution of timers, and wheelSize slots per level, rounded up to a power
+// of two. Non-positive values default to one millisecond and 256 slots;
+// a nil clock uses the system clock.
+func NewTimingWheel(tick time.Duration, wheelSize int, clock Clock) *TimingWheel {
+	if tick <= 0 {
+		tick = defaultTick
+	}
+	if wheelSize <= 1 {
+		wheelSize = defaultWheelSize
+	}
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	b := bits.Len(uint(wheelSize - 1))
+	return &TimingWheel{
+		clock:  clock,
+		tick:   tick,
+		start:  clock.Now(),
+		bits:   b,
+		mask:   1<<b - 1,
+		levels: [][]timerList{make([]timerList, 1<<b)},
+	}
+}
+
+// Schedule arranges for fn to run once delay has passed and returns the
+// timer's handle. fn runs on the goroutine that advances the wheel, after
+// the wheel's lock is released, so it may schedule, cancel or reset timers.
+func (w *TimingWheel) Schedule(delay time.Duration, fn func()) *Timer {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	t := &Timer{fn: fn}
+	t.expiry = w.expiryTick(delay)
+	w.insert(t)
+	w.len++
+	return t
+}
+
+// Cancel stops t. It returns false if t has already fired or been cancelled.
+func (w *TimingWheel) Cancel(t *Timer) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if t.list == nil {
+		return false
+	}
+	t.list.remove(t)
+	w.len--
+	return true
+}
+
+// Reset reschedules t to fire once delay has passed from now, whether or
+// not it is still pending. It returns true if t was pending. A callback
+// already taken for running by an advance still runs.
+func (w *TimingWheel) Reset(t *Timer, delay time.Duration) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	pending := t.list != nil
+	if pending {
+		t.list.remove(t)
+	} else {
+		w.len++
+	}
+	t.expiry = w.expiryTick(delay)
+	w.insert(t)
+	return pending
+}
+
+// Len returns the number of pending timers.
+func (w *TimingWheel) Len() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.len
+}
+
+// AdvanceTo processes every tick up to now, runs the callbacks of the
+// timers that became due in expiry order, and returns how many ran.
+// Advancing to a time already passed has no effect.
+func (w *TimingWheel) AdvanceTo(now time.Time) int {
+	w.mu.Lock()
+	var due []func()
+	if elapsed := now.Sub(w.start); elapsed >= 0 {
+		target := uint64(elapsed / w.tick)
+		for w.next <= target {
+			if w.len == 0 {
+				// nothing to cascade or fire in the ticks left
+				w.next = target + 1
+				break
+			}
+			due = w.processTick(due)
+		}
+	}
+	w.mu.Unlock()
+
+	for _, fn := range due {
+		fn()
+	}
+	return len(due)
+}
+
+// Start advances the wheel to the clock's time every tick until ctx is done.
+func (w *TimingWheel) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.tick)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.AdvanceTo(w.clock.Now())
+			}
+		}
+	}()
+}
+
+// expiryTick returns the first tick at or after delay from now.
+// Callers must hold w.mu.
+func (w *TimingWheel) expiryTick(delay time.Duration) uint64 {
+	elapsed := w.clock.Now().Sub(w.start) + delay
+	if elapsed <= 0 {
+		return w.next
+	}
+	return max(uint64((elapsed+w.tick-1)/w.tick), w.next)
+}
+
+// insert queues t in the level and slot for its expiry relative to w.next,
+// adding levels as needed. Callers must hold w.mu.
+func (w *TimingWheel) insert(t *Timer) {
+	delta := t.expiry - w.next
+	level := 0
+	for shift := w.bits; shift < 64 && delta>>shift != 0; shift += w.bits {
+		level++
+	}
+	for len(w.levels) <= level {
+		w.levels = append(w.levels, make([]timerList, w.mask+1))
+	}
+
+	slot := (t.expiry >> (level * w.bits)) & w.mask
+	w.levels[level][slot].pushBack(t)
+}
+
+// processTick cascades the higher levels due at w.next into lower ones,
+// collects the callbacks of the timers expiring at it and advances w.next.
+// Callers must hold w.mu.
+func (w *TimingWheel) processTick(due []func()) []func() {
+	tick := w.next
+	// a level is due once every slot of the level below has been processed
+	for level := 1; level < len(w.levels); level++ {
+		shift := (level - 1) * w.bits
+		if (tick>>shift)&w.mask != 0 {
+			break
+		}
+		w.cascade(level, (tick>>(level*w.bits))&w.mask)
+	}
+
+	list := &w.levels[0][tick&w.mask]
+	for t := list.head; t != nil; t = list.head {
+		list.remove(t)
+		w.len--
+		due = append(due, t.fn)
+	}
+	w.next++
+	return due
+}
+
+// cascade re-inserts the timers of a higher-level slot relative to w.next,
+// which moves them to lower levels. Callers must hold w.mu.
+func (w *TimingWheel) cascade(level int, slot uint64) {
+	list := &w.levels[level][slot]
+	for t := list.head; t != nil; t = list.head {
+		list.remove(t)
+		w.insert(t)
+	}
+}
+
+func (l *timerList) pushBack(t *Timer) {
+	t.list = l
+	t.prev = l.tail
+	t.next = nil
+	if l.tail != nil {
+		l.tail.next = t
+	} else {
+		l.head = t
+	}
+	l.tail = t
+}
+
+func (l *timerList) remove(t *Timer) {
+	if t.prev != nil {
+		t.prev.next = t.next
+	} else {
+		l.head = t.next
+	}
+	if t.next != nil {
+		t.next.prev = t.prev
+	} else {
+		l.tail = t.prev
+	}
+	t.list, t.prev, t.next = nil, nil, nil
+}
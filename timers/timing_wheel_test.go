@@ -0,0 +1,254 @@
+package timers
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) Advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+	return c.t
+}
+
+func newFakeWheel(tick time.Duration, size int) (*TimingWheel, *fakeClock) {
+	clock := &fakeClock{t: time.Unix(1_000, 0)}
+	return NewTimingWheel(tick, size, clock), clock
+}
+
+func TestTimingWheel_FiresAfterDelay(t *testing.T) {
+	w, clock := newFakeWheel(time.Millisecond, 8)
+	fired := 0
+	w.Schedule(5*time.Millisecond, func() { fired++ })
+
+	if n := w.AdvanceTo(clock.Advance(4 * time.Millisecond)); n != 0 || fired != 0 {
+		t.Fatalf("expected timer not to fire early, fired %d", fired)
+	}
+	if n := w.AdvanceTo(clock.Advance(time.Millisecond)); n != 1 || fired != 1 {
+		t.Fatalf("expected timer to fire after its delay, got n=%d fired=%d", n, fired)
+	}
+	if w.Len() != 0 {
+		t.Fatalf("expected no pending timers, got %d", w.Len())
+	}
+	if n := w.AdvanceTo(clock.Advance(time.Second)); n != 0 || fired != 1 {
+		t.Fatalf("expected timer to fire once, fired %d", fired)
+	}
+}
+
+func TestTimingWheel_ZeroAndNegativeDelay(t *testing.T) {
+	w, clock := newFakeWheel(time.Millisecond, 8)
+	fired := 0
+	w.Schedule(0, func() { fired++ })
+	w.Schedule(-time.Second, func() { fired++ })
+	if n := w.AdvanceTo(clock.Now()); n != 2 {
+		t.Fatalf("expected due timers to fire on the next advance, got %d", n)
+	}
+}
+
+func TestTimingWheel_FiresInExpiryOrderAcrossLevels(t *testing.T) {
+	w, clock := newFakeWheel(time.Millisecond, 4)
+	var order []int
+	for _, ms := range []int{300, 7, 64, 1, 1000, 16} {
+		w.Schedule(time.Duration(ms)*time.Millisecond, func() { order = append(order, ms) })
+	}
+	if len(w.levels) < 4 {
+		t.Fatalf("expected long delays to add levels, got %d", len(w.levels))
+	}
+
+	w.AdvanceTo(clock.Advance(2 * time.Second))
+	want := []int{1, 7, 16, 64, 300, 1000}
+	if len(order) != len(want) {
+		t.Fatalf("expected %v, got %v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, order)
+		}
+	}
+}
+
+func TestTimingWheel_RandomizedAgainstReference(t *testing.T) {
+	const tick = time.Millisecond
+	w, clock := newFakeWheel(tick, 8)
+	rnd := rand.New(rand.NewPCG(1, 2))
+
+	type scheduled struct {
+		deadline time.Time
+		firedAt  time.Time
+		fired    int
+	}
+	var now time.Time
+	var all []*scheduled
+	for step := 0; step < 2000; step++ {
+		for i := rnd.IntN(4); i > 0; i-- {
+			s := &scheduled{deadline: clock.Now().Add(time.Duration(rnd.IntN(5000)) * tick)}
+			all = append(all, s)
+			w.Schedule(s.deadline.Sub(clock.Now()), func() {
+				s.fired++
+				s.firedAt = now
+			})
+		}
+		now = clock.Advance(time.Duration(rnd.IntN(20)) * tick)
+		w.AdvanceTo(now)
+		for _, s := range all {
+			// due timers fire on the first advance past their deadline
+			if due := !now.Before(s.deadline); due != (s.fired == 1) || s.fired > 1 {
+				t.Fatalf("timer due at %v: fired %d times by %v", s.deadline, s.fired, now)
+			}
+		}
+	}
+	for _, s := range all {
+		if s.fired == 1 && s.firedAt.Sub(s.deadline) >= 20*tick {
+			t.Fatalf("timer due at %v fired late at %v", s.deadline, s.firedAt)
+		}
+	}
+}
+
+func TestTimingWheel_Cancel(t *testing.T) {
+	w, clock := newFakeWheel(time.Millisecond, 8)
+	fired := false
+	timer := w.Schedule(100*time.Millisecond, func() { fired = true })
+	w.Schedule(100*time.Millisecond, func() {})
+
+	if !w.Cancel(timer) {
+		t.Fatal("expected Cancel of pending timer to succeed")
+	}
+	if w.Cancel(timer) {
+		t.Fatal("expected repeated Cancel to fail")
+	}
+	if w.Len() != 1 {
+		t.Fatalf("expected 1 pending timer, got %d", w.Len())
+	}
+	if n := w.AdvanceTo(clock.Advance(time.Second)); n != 1 || fired {
+		t.Fatalf("expected cancelled timer not to fire, got n=%d fired=%v", n, fired)
+	}
+
+	fire := w.Schedule(time.Millisecond, func() {})
+	w.AdvanceTo(clock.Advance(time.Millisecond))
+	if w.Cancel(fire) {
+		t.Fatal("expected Cancel of fired timer to fail")
+	}
+}
+
+func TestTimingWheel_Reset(t *testing.T) {
+	w, clock := newFakeWheel(time.Millisecond, 8)
+	fired := 0
+	timer := w.Schedule(10*time.Millisecond, func() { fired++ })
+
+	clock.Advance(8 * time.Millisecond)
+	if !w.Reset(timer, 10*time.Millisecond) {
+		t.Fatal("expected Reset of pending timer to report it pending")
+	}
+	w.AdvanceTo(clock.Advance(5 * time.Millisecond))
+	if fired != 0 {
+		t.Fatal("expected Reset to postpone the timer")
+	}
+	w.AdvanceTo(clock.Advance(5 * time.Millisecond))
+	if fired != 1 {
+		t.Fatalf("expected timer to fire after reset delay, fired %d", fired)
+	}
+
+	// re-arming a fired timer
+	if w.Reset(timer, time.Millisecond) {
+		t.Fatal("expected Reset of fired timer to report it not pending")
+	}
+	if w.Len() != 1 {
+		t.Fatalf("expected 1 pending timer, got %d", w.Len())
+	}
+	w.AdvanceTo(clock.Advance(time.Millisecond))
+	if fired != 2 {
+		t.Fatalf("expected re-armed timer to fire, fired %d", fired)
+	}
+}
+
+func TestTimingWheel_CallbackMayUseWheel(t *testing.T) {
+	w, clock := newFakeWheel(time.Millisecond, 8)
+	retries := 0
+	var retry func()
+	retry = func() {
+		retries++
+		if retries < 3 {
+			w.Schedule(time.Millisecond, retry)
+		}
+	}
+	w.Schedule(time.Millisecond, retry)
+
+	for i := 0; i < 5; i++ {
+		w.AdvanceTo(clock.Advance(time.Millisecond))
+	}
+	if retries != 3 {
+		t.Fatalf("expected 3 runs, got %d", retries)
+	}
+}
+
+func TestTimingWheel_AdvanceToPastTime(t *testing.T) {
+	w, clock := newFakeWheel(time.Millisecond, 8)
+	w.Schedule(time.Millisecond, func() {})
+	if n := w.AdvanceTo(clock.Now().Add(-time.Hour)); n != 0 {
+		t.Fatalf("expected no timers to fire, got %d", n)
+	}
+}
+
+func TestTimingWheel_Start(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := NewTimingWheel(time.Millisecond, 0, nil)
+	w.Start(ctx)
+
+	done := make(chan struct{})
+	w.Schedule(5*time.Millisecond, func() { close(done) })
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected timer to fire with the system clock")
+	}
+}
+
+func TestTimingWheel_Concurrent(t *testing.T) {
+	w, clock := newFakeWheel(time.Millisecond, 16)
+	var fired atomic.Int64
+	wg := sync.WaitGroup{}
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				timer := w.Schedule(time.Duration(i)*time.Millisecond, func() { fired.Add(1) })
+				if i%4 == 0 {
+					w.Cancel(timer)
+				}
+				w.AdvanceTo(clock.Now())
+			}
+		}()
+	}
+	wg.Wait()
+	w.AdvanceTo(clock.Advance(time.Second))
+	if fired.Load() != 600 || w.Len() != 0 {
+		t.Fatalf("expected 600 fired timers and none pending, got %d fired and %d pending", fired.Load(), w.Len())
+	}
+}
+
+func BenchmarkTimingWheel_ScheduleCancel(b *testing.B) {
+	w := NewTimingWheel(time.Millisecond, 256, nil)
+	fn := func() {}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		w.Cancel(w.Schedule(time.Duration(i%60_000)*time.Millisecond, fn))
+	}
+}